@@ -0,0 +1,107 @@
+package cobra
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	// activeHelpMarker prefixes the completion lines which are hints to be
+	// printed by the completion scripts instead of being used as completions.
+	activeHelpMarker = "_activeHelp_ "
+	// activeHelpEnvVarSuffix is appended to the program name to form the
+	// environment variable controlling active help.
+	activeHelpEnvVarSuffix = "_ACTIVE_HELP"
+)
+
+// AppendActiveHelp adds the specified hint to the completions returned by a
+// completion function.  Instead of being offered as a completion choice,
+// such a hint is printed by the shell below the command-line.
+//
+// Example:
+//   comps = cobra.AppendActiveHelp(comps, "You must first choose a cluster")
+func AppendActiveHelp(completions []string, help string) []string {
+	return append(completions, activeHelpMarker+help)
+}
+
+// ActiveHelpEnvVar returns the name of the environment variable which can be
+// set to 0 by the user to disable active help for the program of the command.
+// For example, the variable is MY_PROGRAM_ACTIVE_HELP for "my-program".
+func (c *Command) ActiveHelpEnvVar() string {
+	name := strings.ToUpper(c.Root().Name())
+	name = strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+	return name + activeHelpEnvVarSuffix
+}
+
+// activeHelpEnabled returns false if the user disabled active help for the
+// program of the command.
+func (c *Command) activeHelpEnabled() bool {
+	return os.Getenv(c.ActiveHelpEnvVar()) != "0"
+}
+
+// isActiveHelp returns true if the completion is an active help hint.
+func isActiveHelp(comp string) bool {
+	return strings.HasPrefix(comp, activeHelpMarker)
+}
+
+// requiredFlagsActiveHelp returns a hint listing the required flags of the
+// command which have not been set, if any.
+func requiredFlagsActiveHelp(cmd *Command) []string {
+	var missing []string
+	doMissingFlags := func(flag *pflag.Flag) {
+		if _, present := flag.Annotations[BashCompOneRequiredFlag]; present && !flag.Changed {
+			missing = append(missing, "--"+flag.Name)
+		}
+	}
+
+	// We cannot use cmd.Flags() because we may not have called ParsedFlags() for commands
+	// that have set DisableFlagParsing; it is ParseFlags() that merges the inherited and
+	// non-inherited flags.
+	cmd.InheritedFlags().VisitAll(doMissingFlags)
+	cmd.NonInheritedFlags().VisitAll(doMissingFlags)
+
+	if len(missing) == 0 {
+		return nil
+	}
+	return AppendActiveHelp(nil, fmt.Sprintf("Required flag(s) %s not set", strings.Join(missing, ", ")))
+}
+
+// maxArgsProbe is the number of additional arguments tried when checking if
+// a command accepts more arguments.
+const maxArgsProbe = 8
+
+// argsActiveHelp returns a hint if the command does not accept the argument
+// being completed, whatever the arguments following it, as is the case
+// when ExactArgs(), MaximumNArgs() or NoArgs() are already satisfied.
+// Since the Args validator cannot be inspected, it is called with more and
+// more arguments, each one a copy of a previous valid argument, or a valid
+// argument from ValidArgs.
+func argsActiveHelp(cmd *Command, args []string, toComplete string) []string {
+	if cmd.Args == nil || cmd.ValidateArgs(args) != nil {
+		return nil
+	}
+
+	placeholder := toComplete
+	if len(args) > 0 {
+		placeholder = args[len(args)-1]
+	} else if len(cmd.ValidArgs) > 0 {
+		placeholder = strings.Split(cmd.ValidArgs[0], "\t")[0]
+	}
+
+	probe := append([]string{}, args...)
+	for i := 0; i < maxArgsProbe; i++ {
+		probe = append(probe, placeholder)
+		if cmd.ValidateArgs(probe) == nil {
+			return nil
+		}
+	}
+	return AppendActiveHelp(nil, "This command does not take any more arguments")
+}
@@ -0,0 +1,184 @@
+package cobra
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestActiveHelpFromValidArgsFunc(t *testing.T) {
+	rootCmd := &Command{
+		Use: "root",
+		ValidArgsFunction: func(cmd *Command, args []string, toComplete string) ([]string, ShellCompDirective) {
+			comps := []string{"cluster1", "cluster2"}
+			return AppendActiveHelp(comps, "Choose a cluster"), ShellCompDirectiveNoFileComp
+		},
+		Run: emptyRun,
+	}
+
+	output, err := executeCommand(rootCmd, ShellCompNoDescRequestCmd, "")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		"cluster1",
+		"cluster2",
+		activeHelpMarker + "Choose a cluster",
+		":4",
+		"Completion ended with directive: ShellCompDirectiveNoFileComp", ""}, "\n")
+
+	if output != expected {
+		t.Errorf("expected: %q, got: %q", expected, output)
+	}
+}
+
+func TestActiveHelpDisabled(t *testing.T) {
+	rootCmd := &Command{
+		Use: "my-root",
+		ValidArgsFunction: func(cmd *Command, args []string, toComplete string) ([]string, ShellCompDirective) {
+			return AppendActiveHelp([]string{"cluster1"}, "Choose a cluster"), ShellCompDirectiveNoFileComp
+		},
+		Run: emptyRun,
+	}
+
+	envVar := rootCmd.ActiveHelpEnvVar()
+	if envVar != "MY_ROOT_ACTIVE_HELP" {
+		t.Errorf("expected: %q, got: %q", "MY_ROOT_ACTIVE_HELP", envVar)
+	}
+	os.Setenv(envVar, "0")
+	defer os.Unsetenv(envVar)
+
+	output, err := executeCommand(rootCmd, ShellCompNoDescRequestCmd, "")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		"cluster1",
+		":4",
+		"Completion ended with directive: ShellCompDirectiveNoFileComp", ""}, "\n")
+
+	if output != expected {
+		t.Errorf("expected: %q, got: %q", expected, output)
+	}
+}
+
+func TestActiveHelpForRequiredFlags(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	childCmd := &Command{
+		Use: "child",
+		ValidArgsFunction: func(cmd *Command, args []string, toComplete string) ([]string, ShellCompDirective) {
+			return []string{"arg1", "arg2"}, ShellCompDirectiveNoFileComp
+		},
+		Run: emptyRun,
+	}
+	rootCmd.AddCommand(childCmd)
+	rootCmd.PersistentFlags().String("cluster", "", "cluster name")
+	rootCmd.MarkPersistentFlagRequired("cluster")
+	childCmd.Flags().String("namespace", "", "namespace")
+	childCmd.MarkFlagRequired("namespace")
+
+	// Test that no hint is given when the required flags are completed
+	output, err := executeCommand(rootCmd, ShellCompNoDescRequestCmd, "child", "")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		"--cluster",
+		"--namespace",
+		"arg1",
+		"arg2",
+		":4",
+		"Completion ended with directive: ShellCompDirectiveNoFileComp", ""}, "\n")
+
+	if output != expected {
+		t.Errorf("expected: %q, got: %q", expected, output)
+	}
+
+	// Test that a hint is given when the required flags are not completed
+	output, err = executeCommand(rootCmd, ShellCompNoDescRequestCmd, "child", "--namespace", "ns", "a")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expected = strings.Join([]string{
+		"arg1",
+		"arg2",
+		activeHelpMarker + "Required flag(s) --cluster not set",
+		":4",
+		"Completion ended with directive: ShellCompDirectiveNoFileComp", ""}, "\n")
+
+	if output != expected {
+		t.Errorf("expected: %q, got: %q", expected, output)
+	}
+}
+
+func TestActiveHelpForArgs(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	childCmd := &Command{
+		Use:       "child",
+		Args:      ExactValidArgs(1),
+		ValidArgs: []string{"one", "two"},
+		Run:       emptyRun,
+	}
+	rootCmd.AddCommand(childCmd)
+
+	// Test that no hint is given while arguments are still accepted
+	output, err := executeCommand(rootCmd, ShellCompNoDescRequestCmd, "child", "")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		"one",
+		"two",
+		":4",
+		"Completion ended with directive: ShellCompDirectiveNoFileComp", ""}, "\n")
+
+	if output != expected {
+		t.Errorf("expected: %q, got: %q", expected, output)
+	}
+
+	// Test that a hint is given once no more arguments are accepted
+	output, err = executeCommand(rootCmd, ShellCompNoDescRequestCmd, "child", "one", "")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expected = strings.Join([]string{
+		activeHelpMarker + "This command does not take any more arguments",
+		":0",
+		"Completion ended with directive: ShellCompDirectiveDefault", ""}, "\n")
+
+	if output != expected {
+		t.Errorf("expected: %q, got: %q", expected, output)
+	}
+}
+
+func TestActiveHelpInScripts(t *testing.T) {
+	rootCmd := &Command{Use: "root", Args: NoArgs, Run: emptyRun}
+
+	buf := new(bytes.Buffer)
+	rootCmd.GenBashCompletion(buf)
+	check(t, buf.String(), activeHelpMarker)
+	check(t, buf.String(), "__root_handle_active_help")
+
+	buf.Reset()
+	rootCmd.GenZshCompletion(buf)
+	check(t, buf.String(), activeHelpMarker)
+
+	buf.Reset()
+	rootCmd.GenFishCompletion(buf, true)
+	check(t, buf.String(), activeHelpMarker)
+
+	buf.Reset()
+	rootCmd.GenPowerShellCompletion(buf)
+	check(t, buf.String(), activeHelpMarker)
+	check(t, buf.String(), ShellCompNoDescRequestCmd)
+	// The typed arguments are passed to the program without being evaluated
+	check(t, buf.String(), "& 'root' @requestArgs")
+	checkOmit(t, buf.String(), "Invoke-Expression")
+}
@@ -1,7 +1,7 @@
 package cobra
 
 import (
-	"fmt"
+	"errors"
 	"strings"
 )
 
@@ -19,7 +19,7 @@ func legacyArgs(cmd *Command, args []string) error {
 
 	// root command with subcommands, do subcommand checking.
 	if !cmd.HasParent() && len(args) > 0 {
-		return fmt.Errorf("unknown command %q for %q%s", args[0], cmd.CommandPath(), cmd.findSuggestions(args[0]))
+		return errors.New(cmd.Translate("unknown command %q for %q", args[0], cmd.CommandPath()) + cmd.findSuggestions(args[0]))
 	}
 	return nil
 }
@@ -27,7 +27,7 @@ func legacyArgs(cmd *Command, args []string) error {
 // NoArgs returns an error if any args are included.
 func NoArgs(cmd *Command, args []string) error {
 	if len(args) > 0 {
-		return fmt.Errorf("unknown command %q for %q", args[0], cmd.CommandPath())
+		return errors.New(cmd.Translate("unknown command %q for %q", args[0], cmd.CommandPath()))
 	}
 	return nil
 }
@@ -44,7 +44,10 @@ func OnlyValidArgs(cmd *Command, args []string) error {
 
 		for _, v := range args {
 			if !stringInSlice(v, validArgs) {
-				return fmt.Errorf("invalid argument %q for %q%s", v, cmd.CommandPath(), cmd.findSuggestions(args[0]))
+				if cmd.suggestValidArgs() {
+					return errors.New(cmd.Translate("invalid argument %q for %q", v, cmd.CommandPath()) + cmd.validArgsSuggestions(v, validArgs))
+				}
+				return errors.New(cmd.Translate("invalid argument %q for %q", v, cmd.CommandPath()) + cmd.findSuggestions(args[0]))
 			}
 		}
 	}
@@ -60,7 +63,7 @@ func ArbitraryArgs(cmd *Command, args []string) error {
 func MinimumNArgs(n int) PositionalArgs {
 	return func(cmd *Command, args []string) error {
 		if len(args) < n {
-			return fmt.Errorf("requires at least %d arg(s), only received %d", n, len(args))
+			return errors.New(cmd.Translate("requires at least %d arg(s), only received %d", n, len(args)))
 		}
 		return nil
 	}
@@ -70,7 +73,7 @@ func MinimumNArgs(n int) PositionalArgs {
 func MaximumNArgs(n int) PositionalArgs {
 	return func(cmd *Command, args []string) error {
 		if len(args) > n {
-			return fmt.Errorf("accepts at most %d arg(s), received %d", n, len(args))
+			return errors.New(cmd.Translate("accepts at most %d arg(s), received %d", n, len(args)))
 		}
 		return nil
 	}
@@ -80,7 +83,7 @@ func MaximumNArgs(n int) PositionalArgs {
 func ExactArgs(n int) PositionalArgs {
 	return func(cmd *Command, args []string) error {
 		if len(args) != n {
-			return fmt.Errorf("accepts %d arg(s), received %d", n, len(args))
+			return errors.New(cmd.Translate("accepts %d arg(s), received %d", n, len(args)))
 		}
 		return nil
 	}
@@ -102,7 +105,7 @@ func ExactValidArgs(n int) PositionalArgs {
 func RangeArgs(min int, max int) PositionalArgs {
 	return func(cmd *Command, args []string) error {
 		if len(args) < min || len(args) > max {
-			return fmt.Errorf("accepts between %d and %d arg(s), received %d", min, max, len(args))
+			return errors.New(cmd.Translate("accepts between %d and %d arg(s), received %d", min, max, len(args)))
 		}
 		return nil
 	}
@@ -204,6 +204,58 @@ func TestExactValidArgsWithInvalidArgs(t *testing.T) {
 	}
 }
 
+func TestOnlyValidArgsSuggestions(t *testing.T) {
+	c := &Command{
+		Use:              "c",
+		Args:             OnlyValidArgs,
+		ValidArgs:        []string{"pods", "nodes\tThe nodes", "secrets"},
+		SuggestValidArgs: true,
+		Run:              emptyRun,
+	}
+
+	_, err := executeCommand(c, "pods", "secret")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	got := err.Error()
+	expected := "invalid argument \"secret\" for \"c\"\n\nDid you mean this?\n\tsecrets\n"
+	if got != expected {
+		t.Errorf("Expected: %q, got: %q", expected, got)
+	}
+
+	c.DisableSuggestions = true
+	_, err = executeCommand(c, "node")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	expected = `invalid argument "node" for "c"`
+	if got := err.Error(); got != expected {
+		t.Errorf("Expected: %q, got: %q", expected, got)
+	}
+}
+
+func TestOnlyValidArgsSuggestionsDisabledOnRoot(t *testing.T) {
+	rootCmd := &Command{Use: "root", DisableSuggestions: true}
+	c := &Command{
+		Use:              "c",
+		Args:             OnlyValidArgs,
+		ValidArgs:        []string{"pods", "secrets"},
+		SuggestValidArgs: true,
+		Run:              emptyRun,
+	}
+	rootCmd.AddCommand(c)
+
+	_, err := executeCommand(rootCmd, "c", "secret")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	expected := `invalid argument "secret" for "root c"`
+	if got := err.Error(); got != expected {
+		t.Errorf("Expected: %q, got: %q", expected, got)
+	}
+}
+
 func TestRangeArgs(t *testing.T) {
 	c := &Command{Use: "c", Args: RangeArgs(2, 4), Run: emptyRun}
 	output, err := executeCommand(c, "a", "b", "c")
@@ -68,28 +68,48 @@ __%[1]s_handle_go_custom_completion()
     local shellCompDirectiveFilterFileExt=%[6]d
     local shellCompDirectiveFilterDirs=%[7]d
 
-    local out requestComp lastParam lastChar comp directive args
+    local out requestComp curWord comp directive args
 
-    # Prepare the command to request completions for the program.
-    # Calling ${words[0]} instead of directly %[1]s allows to handle aliases
-    args=("${words[@]:1}")
-    requestComp="${words[0]} %[2]s ${args[*]}"
-
-    lastParam=${words[$((${#words[@]}-1))]}
-    lastChar=${lastParam:$((${#lastParam}-1)):1}
-    __%[1]s_debug "${FUNCNAME[0]}: lastParam ${lastParam}, lastChar ${lastChar}"
+    # The word being completed only goes up to the cursor, unless the value
+    # of a flag with an = was split from it (e.g., --flag=<TAB>)
+    curWord=${words[cword]}
+    if [[ ${curWord} == "${cur}"* ]] && [[ -n "${cur}" || ${curWord} != *= ]]; then
+        curWord=${cur}
+    fi
+    __%[1]s_debug "${FUNCNAME[0]}: curWord ${curWord}, cword ${cword}"
 
-    if [ -z "${cur}" ] && [ "${lastChar}" != "=" ]; then
-        # If the last parameter is complete (there is a space following it)
+    # Prepare the command to request completions for the program.
+    # Calling ${words[0]} instead of directly %[1]s allows to handle aliases.
+    # The words following the cursor are also sent, along with the index of
+    # the word being completed counted from the end.
+    args=("${words[@]:1:cword-1}")
+    if [ -z "${curWord}" ]; then
+        # If the word being completed is empty (there is a space before the cursor)
         # We add an extra empty parameter so we can indicate this to the go method.
         __%[1]s_debug "${FUNCNAME[0]}: Adding extra empty parameter"
-        requestComp="${requestComp} \"\""
+        args+=("\"\"")
+    else
+        args+=("${curWord}")
     fi
+    args+=("${words[@]:cword+1}")
+    requestComp="${words[0]} %[2]s %[9]s=-$((${#words[@]}-cword)) ${args[*]}"
 
     __%[1]s_debug "${FUNCNAME[0]}: calling ${requestComp}"
     # Use eval to handle any environment variables and such
     out=$(eval "${requestComp}" 2>/dev/null)
 
+    # Separate the active help hints from the completions and the directive
+    local activeHelpMarker="%[8]s" line lines=()
+    activeHelp=()
+    while IFS='' read -r line; do
+        if [[ ${line} == "${activeHelpMarker}"* ]]; then
+            activeHelp+=("${line#"${activeHelpMarker}"}")
+        else
+            lines+=("${line}")
+        fi
+    done < <(printf "%%s\n" "${out}")
+    out=$(printf "%%s\n" "${lines[@]}")
+
     # Extract the directive integer at the very end of the output following a colon (:)
     directive=${out##*:}
     # Remove the directive
@@ -100,6 +120,7 @@ __%[1]s_handle_go_custom_completion()
     fi
     __%[1]s_debug "${FUNCNAME[0]}: the completion directive is: ${directive}"
     __%[1]s_debug "${FUNCNAME[0]}: the completions are: ${out[*]}"
+    __%[1]s_debug "${FUNCNAME[0]}: the active help is: ${activeHelp[*]}"
 
     if [ $((directive & shellCompDirectiveError)) -ne 0 ]; then
         # Error code.  No completion.
@@ -151,6 +172,36 @@ __%[1]s_handle_go_custom_completion()
     fi
 }
 
+# Print the active help hints below the command-line
+__%[1]s_handle_active_help()
+{
+    if [ ${#activeHelp[@]} -eq 0 ]; then
+        return
+    fi
+    __%[1]s_debug "${FUNCNAME[0]}: printing active help"
+
+    # Bash does not set COMP_TYPE before version 4; otherwise only print
+    # the hints when listing the completions, on the second TAB press.
+    if [ -n "${COMP_TYPE}" ] && [ "${COMP_TYPE}" -ne 63 ]; then
+        return
+    fi
+
+    printf "\n"
+    printf "%%s\n" "${activeHelp[@]}"
+    if [ ${#COMPREPLY[@]} -ne 0 ]; then
+        # The shell will re-print the command-line after the completions
+        printf -- "--"
+    else
+        # The shell will not re-print the command-line, so we have to.
+        # The prompt format is only available from bash 4.4.
+        if (x=${PS1@P}) 2> /dev/null; then
+            printf "%%s" "${PS1@P}${COMP_LINE}"
+        else
+            printf "%%s" "${COMP_LINE}"
+        fi
+    fi
+}
+
 __%[1]s_handle_reply()
 {
     __%[1]s_debug "${FUNCNAME[0]}"
@@ -377,7 +428,7 @@ __%[1]s_handle_word()
 
 `, name, ShellCompNoDescRequestCmd,
 		ShellCompDirectiveError, ShellCompDirectiveNoSpace, ShellCompDirectiveNoFileComp,
-		ShellCompDirectiveFilterFileExt, ShellCompDirectiveFilterDirs))
+		ShellCompDirectiveFilterFileExt, ShellCompDirectiveFilterDirs, activeHelpMarker, ShellCompCursorArg))
 }
 
 func writePostscript(buf *bytes.Buffer, name string) {
@@ -405,8 +456,10 @@ func writePostscript(buf *bytes.Buffer, name string) {
     local has_completion_function
     local last_command
     local nouns=()
+    local activeHelp=()
 
     __%[1]s_handle_word
+    __%[1]s_handle_active_help
 }
 
 `, name))
@@ -517,6 +570,29 @@ func prepareCustomAnnotationsForFlags(cmd *Command) {
 		}
 		flag.Annotations[BashCompCustom] = []string{fmt.Sprintf("__%[1]s_handle_go_custom_completion", cmd.Root().Name())}
 	}
+
+	// Flags with a static list of values are also completed by the Go code,
+	// as well as flags with a default completion for their type which have no
+	// other completion.  Bool flags are excluded since they do not take a
+	// following argument, which the script would then complete as a value.
+	setForValues := func(flag *pflag.Flag) {
+		if len(flagValues(flag)) == 0 {
+			if _, _, ok := defaultFlagCompletion(flag, ""); !ok || flag.Value.Type() == "bool" {
+				return
+			}
+			for _, key := range []string{BashCompCustom, BashCompFilenameExt, BashCompSubdirsInDir} {
+				if _, present := flag.Annotations[key]; present {
+					return
+				}
+			}
+		}
+		if flag.Annotations == nil {
+			flag.Annotations = map[string][]string{}
+		}
+		flag.Annotations[BashCompCustom] = []string{fmt.Sprintf("__%[1]s_handle_go_custom_completion", cmd.Root().Name())}
+	}
+	cmd.NonInheritedFlags().VisitAll(setForValues)
+	cmd.InheritedFlags().VisitAll(setForValues)
 }
 
 func writeFlags(buf *bytes.Buffer, cmd *Command) {
@@ -650,6 +726,7 @@ func gen(buf *bytes.Buffer, cmd *Command) {
 
 // GenBashCompletion generates bash completion file and writes to the passed writer.
 func (c *Command) GenBashCompletion(w io.Writer) error {
+	_ = c.ResolveCommands()
 	buf := new(bytes.Buffer)
 	writePreamble(buf, c.Name())
 	if len(c.BashCompletionFunction) > 0 {
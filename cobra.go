@@ -41,7 +41,8 @@ var initializers []func()
 
 // EnablePrefixMatching allows to set automatic prefix matching. Automatic prefix matching can be a dangerous thing
 // to automatically enable in CLI tools.
-// Set this to true to enable it.
+// Set this to true to enable it for all the commands, or use Command.PrefixMatching
+// to enable it for some commands only.
 var EnablePrefixMatching = false
 
 // EnableCommandSorting controls sorting of the slice of commands, which is turned on by default.
@@ -156,7 +157,11 @@ func rpad(s string, padding int) string {
 // tmpl executes the given template text on data, writing the result to w.
 func tmpl(w io.Writer, text string, data interface{}) error {
 	t := template.New("top")
-	t.Funcs(templateFuncs)
+	if c, ok := data.(*Command); ok {
+		t.Funcs(c.TemplateFuncs())
+	} else {
+		t.Funcs(templateFuncs)
+	}
 	template.Must(t.Parse(text))
 	return t.Execute(w, data)
 }
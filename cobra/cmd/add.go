@@ -16,14 +16,17 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 	"unicode"
 
 	"github.com/spf13/cobra"
 )
 
 var (
-	packageName string
-	parentName  string
+	packageName   string
+	parentName    string
+	packageLayout bool
+	force         bool
 
 	addCmd = &cobra.Command{
 		Use:     "add [command name]",
@@ -36,7 +39,17 @@ and register it to its parent (default rootCmd).
 If you want your command to be public, pass in the command name
 with an initial uppercase letter.
 
-Example: cobra add server -> resulting in a new cmd/server.go`,
+A nested command is given by its path from the root command, and
+is registered to its parent command.  With --package-layout, each
+command is placed in its own package.  Existing files are only
+overwritten with --force.  Unless --license is given, the license
+of the new files is detected from the existing ones or from the
+LICENSE file.
+
+Example: cobra add server -> resulting in a new cmd/server.go
+         cobra add user/create -> resulting in a new cmd/userCreate.go
+         cobra add --package-layout user/create -> resulting in a new
+           cmd/user/create/create.go, registered by cmd/user/create.go`,
 
 		Run: func(cmd *cobra.Command, args []string) {
 			if len(args) < 1 {
@@ -48,15 +61,18 @@ Example: cobra add server -> resulting in a new cmd/server.go`,
 				er(err)
 			}
 
-			commandName := validateCmdName(args[0])
-			command := &Command{
-				CmdName:   commandName,
-				CmdParent: parentName,
-				Project: &Project{
-					AbsolutePath: wd,
-					Legal:        getLicense(),
-					Copyright:    copyrightLine(),
-				},
+			command, err := newCommand(args[0], parentName, cmd.Flags().Changed("parent"), packageLayout)
+			if err != nil {
+				er(err)
+			}
+			command.Force = force
+			command.Project = &Project{
+				AbsolutePath: wd,
+				PkgName:      modulePath(wd),
+				Legal:        projectLicense(wd),
+				Copyright:    copyrightLine(),
+				TemplateDir:  setting("template-dir", "templateDir", ""),
+				Vars:         templateVars(),
 			}
 
 			err = command.Create()
@@ -64,7 +80,7 @@ Example: cobra add server -> resulting in a new cmd/server.go`,
 				er(err)
 			}
 
-			fmt.Printf("%s created at %s\n", command.CmdName, command.AbsolutePath)
+			fmt.Printf("%s created at %s\n", command.CmdPath, command.AbsolutePath)
 		},
 	}
 )
@@ -72,9 +88,49 @@ Example: cobra add server -> resulting in a new cmd/server.go`,
 func init() {
 	addCmd.Flags().StringVarP(&packageName, "package", "t", "", "target package name (e.g. github.com/spf13/hugo)")
 	addCmd.Flags().StringVarP(&parentName, "parent", "p", "rootCmd", "variable name of parent command for this command")
+	addCmd.Flags().BoolVar(&packageLayout, "package-layout", false, "place the command in its own package")
+	addCmd.Flags().BoolVarP(&force, "force", "f", false, "overwrite existing files")
 	addCmd.Flags().MarkDeprecated("package", "this operation has been removed.")
 }
 
+// newCommand returns the command to add at path, e.g. "user/create", whose
+// name and parent are derived from the path.  The parent is the given parent
+// for a top-level command, or if it has been set explicitly.
+func newCommand(path, parent string, parentSet, packageLayout bool) (*Command, error) {
+	var segments []string
+	for _, s := range strings.Split(strings.Trim(path, "/"), "/") {
+		if s != "" {
+			segments = append(segments, validateCmdName(s))
+		}
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("invalid command path %q", path)
+	}
+	command := &Command{
+		CmdName:       commandIdentifier(segments),
+		CmdPath:       strings.Join(segments, "/"),
+		CmdParent:     parent,
+		PackageLayout: packageLayout,
+	}
+	if len(segments) > 1 && !parentSet {
+		command.CmdParent = commandIdentifier(segments[:len(segments)-1]) + "Cmd"
+		if packageLayout {
+			command.CmdParent = "Cmd"
+		}
+	}
+	return command, nil
+}
+
+// commandIdentifier returns the names of the commands on a path joined in
+// camelCase, e.g. "userCreate" for "user" and "create".
+func commandIdentifier(segments []string) string {
+	identifier := segments[0]
+	for _, s := range segments[1:] {
+		identifier += strings.ToUpper(s[:1]) + s[1:]
+	}
+	return identifier
+}
+
 // validateCmdName returns source without any dashes and underscore.
 // If there will be dash or underscore, next letter will be uppered.
 // It supports only ASCII (1-byte character) strings.
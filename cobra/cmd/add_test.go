@@ -3,6 +3,8 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -27,6 +29,115 @@ func TestGoldenAddCmd(t *testing.T) {
 	}
 }
 
+// addCommands adds the commands at paths to project, with the package layout
+// if packageLayout is set.
+func addCommands(t *testing.T, project *Project, packageLayout bool, paths ...string) {
+	for _, path := range paths {
+		command, err := newCommand(path, "rootCmd", false, packageLayout)
+		if err != nil {
+			t.Fatal(err)
+		}
+		command.Project = project
+		if err := command.Create(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGoldenAddNestedCmd(t *testing.T) {
+	project := getProject()
+	project.Copyright = "Copyright © 2020 NAME HERE <EMAIL ADDRESS>"
+	defer os.RemoveAll(project.AbsolutePath)
+
+	if err := project.Create(); err != nil {
+		t.Fatal(err)
+	}
+	addCommands(t, project, false, "user", "user/create")
+
+	generatedFile := filepath.Join(project.AbsolutePath, "cmd", "userCreate.go")
+	if err := compareFiles(generatedFile, "testdata/userCreate.go.golden"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGoldenAddCmdPackageLayout(t *testing.T) {
+	project := getProject()
+	project.Copyright = "Copyright © 2020 NAME HERE <EMAIL ADDRESS>"
+	defer os.RemoveAll(project.AbsolutePath)
+
+	if err := project.Create(); err != nil {
+		t.Fatal(err)
+	}
+	addCommands(t, project, true, "user", "user/create")
+
+	files := map[string]string{
+		"cmd/user.go":               "testdata/package_layout/user.go.golden",
+		"cmd/user/user.go":          "testdata/package_layout/user/user.go.golden",
+		"cmd/user/create.go":        "testdata/package_layout/user/create.go.golden",
+		"cmd/user/create/create.go": "testdata/package_layout/user/create/create.go.golden",
+	}
+	for generated, golden := range files {
+		if err := compareFiles(filepath.Join(project.AbsolutePath, generated), golden); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAddCmdErrors(t *testing.T) {
+	project := getProject()
+	defer os.RemoveAll(project.AbsolutePath)
+
+	if err := project.Create(); err != nil {
+		t.Fatal(err)
+	}
+	addCommands(t, project, false, "user")
+
+	command, _ := newCommand("group/create", "rootCmd", false, false)
+	command.Project = project
+	if err := command.Create(); err == nil || !strings.Contains(err.Error(), `parent command variable "groupCmd" not found`) {
+		t.Errorf("Expected an error for the missing parent, got %v", err)
+	}
+
+	command, _ = newCommand("user", "rootCmd", false, false)
+	command.Project = project
+	if err := command.Create(); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Expected an error for the existing file, got %v", err)
+	}
+	command.Force = true
+	if err := command.Create(); err != nil {
+		t.Errorf("Expected the existing file to be overwritten, got %v", err)
+	}
+}
+
+func TestNewCommand(t *testing.T) {
+	testCases := []struct {
+		path, parent  string
+		parentSet     bool
+		packageLayout bool
+		name, use     string
+		expected      string
+	}{
+		{"serve", "rootCmd", false, false, "serve", "serve", "rootCmd"},
+		{"create", "configCmd", true, false, "create", "create", "configCmd"},
+		{"user/create", "rootCmd", false, false, "userCreate", "create", "userCmd"},
+		{"user/add-role", "rootCmd", false, false, "userAddRole", "addRole", "userCmd"},
+		{"user/role/add", "rootCmd", false, false, "userRoleAdd", "add", "userRoleCmd"},
+		{"user/create", "otherCmd", true, false, "userCreate", "create", "otherCmd"},
+		{"user/create", "rootCmd", false, true, "userCreate", "create", "Cmd"},
+	}
+
+	for _, testCase := range testCases {
+		command, err := newCommand(testCase.path, testCase.parent, testCase.parentSet, testCase.packageLayout)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if command.CmdName != testCase.name || command.CmdUse() != testCase.use || command.CmdParent != testCase.expected {
+			t.Errorf("Expected %q, %q and %q for %q, got %q, %q and %q", testCase.name, testCase.use, testCase.expected,
+				testCase.path, command.CmdName, command.CmdUse(), command.CmdParent)
+		}
+	}
+}
+
 func TestValidateCmdName(t *testing.T) {
 	testCases := []struct {
 		input    string
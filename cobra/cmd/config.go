@@ -0,0 +1,209 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// configNames are the names of the configuration file searched in the home
+// directory when no --config flag is given.
+var configNames = []string{".cobra.yaml", ".cobra.yml", ".cobra.json"}
+
+// envPrefix is the prefix of the environment variables overriding the
+// settings of the configuration, e.g. COBRA_AUTHOR for author, or
+// COBRA_LICENSE_HEADER for license.header.
+const envPrefix = "COBRA_"
+
+// config holds the settings of the configuration file, by lower-case key.
+// The nested settings, such as license.header, are held by nested configs.
+type config map[string]interface{}
+
+// cfg is the configuration read from the configuration file.
+var cfg = config{}
+
+// readConfig reads the YAML or JSON configuration file at path.
+func readConfig(path string) (config, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	raw := make(map[interface{}]interface{})
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return newConfig(raw), nil
+}
+
+// newConfig returns the configuration holding the settings of raw, with
+// lower-case keys.
+func newConfig(raw map[interface{}]interface{}) config {
+	c := make(config, len(raw))
+	for key, value := range raw {
+		if m, ok := value.(map[interface{}]interface{}); ok {
+			value = newConfig(m)
+		}
+		c[strings.ToLower(fmt.Sprint(key))] = value
+	}
+	return c
+}
+
+// get returns the setting of key, whose parts are separated by dots, e.g.
+// "license.header", and true if it is set, either by its environment
+// variable or by the configuration.  The keys are case-insensitive.
+func (c config) get(key string) (interface{}, bool) {
+	env := envPrefix + strings.ToUpper(strings.Replace(key, ".", "_", -1))
+	if value, ok := os.LookupEnv(env); ok {
+		return value, true
+	}
+	return c.lookup(key)
+}
+
+// lookup returns the setting of key in the configuration, and true if it is
+// set.
+func (c config) lookup(key string) (interface{}, bool) {
+	parts := strings.SplitN(strings.ToLower(key), ".", 2)
+	value, ok := c[parts[0]]
+	if !ok || len(parts) == 1 {
+		return value, ok
+	}
+	nested, ok := value.(config)
+	if !ok {
+		return nil, false
+	}
+	return nested.lookup(parts[1])
+}
+
+// isSet checks if the setting of key is set.
+func (c config) isSet(key string) bool {
+	_, ok := c.get(key)
+	return ok
+}
+
+// getString returns the setting of key as a string, or an empty string if
+// it is not set or is not a scalar.
+func (c config) getString(key string) string {
+	value, _ := c.get(key)
+	return scalarString(value)
+}
+
+// getStringMap returns the nested settings of key in the configuration as
+// strings.
+func (c config) getStringMap(key string) map[string]string {
+	m := make(map[string]string)
+	if value, ok := c.lookup(key); ok {
+		if nested, ok := value.(config); ok {
+			for k, v := range nested {
+				m[k] = scalarString(v)
+			}
+		}
+	}
+	return m
+}
+
+// scalarString returns the setting value as a string, or an empty string if
+// it is not set or is not a scalar.
+func scalarString(value interface{}) string {
+	if _, nested := value.(config); value == nil || nested {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// setting returns the value of the persistent flag of the root command if it
+// has been set, or else the setting of key in the configuration, or else def.
+func setting(flag, key, def string) string {
+	if f := rootCmd.PersistentFlags().Lookup(flag); f != nil && f.Changed {
+		return f.Value.String()
+	}
+	if cfg.isSet(key) {
+		return cfg.getString(key)
+	}
+	return def
+}
+
+// boolSetting returns the boolean value of setting(flag, key, def).
+func boolSetting(flag, key string, def bool) bool {
+	value, err := strconv.ParseBool(setting(flag, key, strconv.FormatBool(def)))
+	if err != nil {
+		er(fmt.Sprintf("invalid value of %s: %v", key, err))
+	}
+	return value
+}
+
+// findConfig returns the path of the configuration file in the home
+// directory, or blank string if there is none.  It also returns the path of
+// a configuration file in an unsupported format, such as ".cobra.toml", if
+// there is one and no supported one.
+func findConfig(home string) (path, unsupported string) {
+	for _, name := range configNames {
+		if candidate := filepath.Join(home, name); exists(candidate) {
+			return candidate, ""
+		}
+	}
+	matches, _ := filepath.Glob(filepath.Join(home, ".cobra.*"))
+	for _, match := range matches {
+		if info, err := os.Stat(match); err == nil && info.Mode().IsRegular() && !isSupportedConfig(match) {
+			return "", match
+		}
+	}
+	return "", ""
+}
+
+// isSupportedConfig returns true if the format of the configuration file at
+// path is supported, as given by its extension.
+func isSupportedConfig(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml", ".hcl", ".ini", ".properties", ".props", ".prop", ".env", ".dotenv":
+		return false
+	}
+	return true
+}
+
+func initConfig() {
+	path := cfgFile
+	if path != "" && !isSupportedConfig(path) {
+		er(fmt.Sprintf("%s: unsupported configuration format, use YAML or JSON", path))
+	}
+	if path == "" {
+		// Search the config in the home directory.
+		home, err := os.UserHomeDir()
+		if err != nil {
+			er(err)
+		}
+		var unsupported string
+		path, unsupported = findConfig(home)
+		if unsupported != "" {
+			fmt.Fprintf(os.Stderr, "Warning: %s is ignored, the configuration file must be %s in the YAML or JSON format\n",
+				unsupported, strings.Join(configNames, ", "))
+		}
+		if path == "" {
+			return
+		}
+	}
+
+	c, err := readConfig(path)
+	if err != nil {
+		er(err)
+	}
+	cfg = c
+	fmt.Println("Using config file:", path)
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cobra-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, ".cobra.yaml")
+	content := `author: Steve Francia <spf@spf13.com>
+year: 2020
+useViper: false
+license:
+  header: This file is part of CLI application foo.
+  text: |
+    {{ .copyright }}
+vars:
+  team: platform
+`
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := readConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := c.getString("author"); got != "Steve Francia <spf@spf13.com>" {
+		t.Errorf("Expected the author, got %q", got)
+	}
+	if got := c.getString("year"); got != "2020" {
+		t.Errorf("Expected the year, got %q", got)
+	}
+	// The keys are case-insensitive
+	if got := c.getString("USEVIPER"); got != "false" {
+		t.Errorf("Expected useViper to be false, got %q", got)
+	}
+	if got := c.getString("license.header"); got != "This file is part of CLI application foo." {
+		t.Errorf("Expected the license header, got %q", got)
+	}
+	if got := c.getString("license"); got != "" {
+		t.Errorf("Expected no license name, got %q", got)
+	}
+	if !c.isSet("license.text") || c.isSet("license.name") || c.isSet("author.name") {
+		t.Error("Expected only license.text to be set")
+	}
+	if got := c.getStringMap("vars"); !reflect.DeepEqual(got, map[string]string{"team": "platform"}) {
+		t.Errorf("Expected the vars, got %v", got)
+	}
+
+	cfg = c
+	defer func() { cfg = config{} }()
+	if boolSetting("viper", "useViper", true) {
+		t.Error("Expected the configuration to disable Viper")
+	}
+	if got := copyrightLine(); got != "Copyright © 2020 Steve Francia <spf@spf13.com>" {
+		t.Errorf("Expected the copyright line of the configuration, got %q", got)
+	}
+	if got := getLicense(); got.Header != "This file is part of CLI application foo." {
+		t.Errorf("Expected the custom license, got %v", got)
+	}
+}
+
+func TestConfigEnv(t *testing.T) {
+	cfg = config{"author": "Steve Francia", "license": config{"header": "From the file"}}
+	defer func() { cfg = config{} }()
+	os.Setenv("COBRA_AUTHOR", "Alice")
+	os.Setenv("COBRA_LICENSE_HEADER", "From the environment")
+	defer os.Unsetenv("COBRA_AUTHOR")
+	defer os.Unsetenv("COBRA_LICENSE_HEADER")
+
+	if got := setting("author", "author", ""); got != "Alice" {
+		t.Errorf("Expected the author of the environment, got %q", got)
+	}
+	if got := cfg.getString("license.header"); got != "From the environment" {
+		t.Errorf("Expected the license header of the environment, got %q", got)
+	}
+	if cfg.isSet("useViper") {
+		t.Error("Expected useViper not to be set")
+	}
+}
+
+func TestFindConfig(t *testing.T) {
+	home, err := ioutil.TempDir("", "cobra-home")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	if path, unsupported := findConfig(home); path != "" || unsupported != "" {
+		t.Errorf("Expected no configuration, got %q, %q", path, unsupported)
+	}
+
+	// The formats of the Viper-based versions which aren't supported
+	toml := filepath.Join(home, ".cobra.toml")
+	if err := ioutil.WriteFile(toml, []byte("author = \"Alice\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if path, unsupported := findConfig(home); path != "" || unsupported != toml {
+		t.Errorf("Expected the unsupported %q, got %q, %q", toml, path, unsupported)
+	}
+
+	yml := filepath.Join(home, ".cobra.yml")
+	if err := ioutil.WriteFile(yml, []byte("author: Alice\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if path, unsupported := findConfig(home); path != yml || unsupported != "" {
+		t.Errorf("Expected %q, got %q, %q", yml, path, unsupported)
+	}
+}
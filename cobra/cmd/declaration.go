@@ -0,0 +1,289 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra/spec"
+)
+
+// Flag declares a flag of a command to add.
+type Flag struct {
+	Name      string
+	Shorthand string
+	// Type is the type of the flag: string, bool, int, int64, uint, float64,
+	// duration, stringSlice, stringArray or intSlice.
+	Type string
+	// Default is the default value of the flag as printed in the help, e.g.
+	// "8080", "5s" or "[a,b]".
+	Default    string
+	Usage      string
+	Persistent bool
+	Required   bool
+}
+
+// flagFuncs maps the types of the flags to the methods of pflag.FlagSet
+// defining them.
+var flagFuncs = map[string]string{
+	"string":      "String",
+	"bool":        "Bool",
+	"int":         "Int",
+	"int64":       "Int64",
+	"uint":        "Uint",
+	"float64":     "Float64",
+	"duration":    "Duration",
+	"stringSlice": "StringSlice",
+	"stringArray": "StringArray",
+	"intSlice":    "IntSlice",
+}
+
+// statements returns the statements defining the flag on the command
+// variable cmdVar.
+func (f Flag) statements(cmdVar string) ([]string, error) {
+	fn, ok := flagFuncs[f.Type]
+	if !ok {
+		return nil, fmt.Errorf("flag %q: unknown type %q", f.Name, f.Type)
+	}
+	value, err := f.literal()
+	if err != nil {
+		return nil, fmt.Errorf("flag %q: invalid default value %q: %v", f.Name, f.Default, err)
+	}
+
+	flags, mark := "Flags", "MarkFlagRequired"
+	if f.Persistent {
+		flags, mark = "PersistentFlags", "MarkPersistentFlagRequired"
+	}
+	args := []string{strconv.Quote(f.Name), value, strconv.Quote(f.Usage)}
+	if f.Shorthand != "" {
+		fn += "P"
+		args = append(args[:1], append([]string{strconv.Quote(f.Shorthand)}, args[1:]...)...)
+	}
+	statements := []string{fmt.Sprintf("%s.%s().%s(%s)", cmdVar, flags, fn, strings.Join(args, ", "))}
+	if f.Required {
+		statements = append(statements, fmt.Sprintf("%s.%s(%q)", cmdVar, mark, f.Name))
+	}
+	return statements, nil
+}
+
+// literal returns the Go expression of the default value of the flag.
+func (f Flag) literal() (string, error) {
+	switch f.Type {
+	case "string":
+		return strconv.Quote(f.Default), nil
+	case "bool":
+		if f.Default == "" {
+			return "false", nil
+		}
+		b, err := strconv.ParseBool(f.Default)
+		return strconv.FormatBool(b), err
+	case "int", "int64":
+		if f.Default == "" {
+			return "0", nil
+		}
+		i, err := strconv.ParseInt(f.Default, 10, 64)
+		return strconv.FormatInt(i, 10), err
+	case "uint":
+		if f.Default == "" {
+			return "0", nil
+		}
+		u, err := strconv.ParseUint(f.Default, 10, 64)
+		return strconv.FormatUint(u, 10), err
+	case "float64":
+		if f.Default == "" {
+			return "0", nil
+		}
+		v, err := strconv.ParseFloat(f.Default, 64)
+		return strconv.FormatFloat(v, 'g', -1, 64), err
+	case "duration":
+		if f.Default == "" {
+			return "0", nil
+		}
+		d, err := time.ParseDuration(f.Default)
+		return durationLiteral(d), err
+	case "stringSlice", "stringArray":
+		values := spec.SliceValues(f.Default)
+		if values == nil {
+			return "nil", nil
+		}
+		return fmt.Sprintf("%#v", values), nil
+	case "intSlice":
+		values := spec.SliceValues(f.Default)
+		if values == nil {
+			return "nil", nil
+		}
+		for _, v := range values {
+			if _, err := strconv.Atoi(v); err != nil {
+				return "", err
+			}
+		}
+		return "[]int{" + strings.Join(values, ", ") + "}", nil
+	}
+	return "", fmt.Errorf("unknown type %q", f.Type)
+}
+
+// durationLiteral returns the Go expression of a duration in the largest
+// unit which divides it, e.g. "90*time.Second".
+func durationLiteral(d time.Duration) string {
+	if d == 0 {
+		return "0"
+	}
+	units := []struct {
+		d    time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+		{time.Microsecond, "time.Microsecond"},
+	}
+	for _, u := range units {
+		if d%u.d == 0 {
+			if d == u.d {
+				return u.name
+			}
+			return fmt.Sprintf("%d*%s", d/u.d, u.name)
+		}
+	}
+	return fmt.Sprintf("%d*time.Nanosecond", d)
+}
+
+// argsExpression returns the Go expression of the positional arguments
+// policy named policy, as parsed by spec.ParsePolicy, or blank string for no
+// policy.
+func argsExpression(policy string) (string, error) {
+	if policy == "" {
+		return "", nil
+	}
+	p, err := spec.ParsePolicy(policy)
+	if err != nil {
+		return "", err
+	}
+	if len(p.Counts) == 0 {
+		return "cobra." + p.Func, nil
+	}
+	counts := make([]string, len(p.Counts))
+	for i, n := range p.Counts {
+		counts[i] = strconv.Itoa(n)
+	}
+	return "cobra." + p.Func + "(" + strings.Join(counts, ", ") + ")", nil
+}
+
+// CmdFields returns the fields of the declaration of the command before its
+// Run function, aligned as by gofmt.
+func (c *Command) CmdFields() []string {
+	use := c.Use
+	if use == "" {
+		use = c.CmdUse()
+	}
+	short := c.Short
+	if short == "" {
+		short = "A brief description of your command"
+	}
+	long := c.Long
+	if long == "" {
+		long = `A longer description that spans multiple lines and likely contains examples
+and usage of using your command. For example:
+
+Cobra is a CLI library for Go that empowers applications.
+This application is a tool to generate the needed files
+to quickly create a Cobra application.`
+	}
+	args, _ := argsExpression(c.Args)
+
+	fields := [][2]string{{"Use", strconv.Quote(use)}}
+	if len(c.Aliases) > 0 {
+		fields = append(fields, [2]string{"Aliases", fmt.Sprintf("%#v", c.Aliases)})
+	}
+	fields = append(fields, [2]string{"Short", strconv.Quote(short)}, [2]string{"Long", stringLiteral(long)})
+	if c.Example != "" {
+		fields = append(fields, [2]string{"Example", stringLiteral(c.Example)})
+	}
+	if args != "" {
+		fields = append(fields, [2]string{"Args", args})
+	}
+	if len(c.ValidArgs) > 0 {
+		fields = append(fields, [2]string{"ValidArgs", fmt.Sprintf("%#v", c.ValidArgs)})
+	}
+
+	// Consecutive single-line fields are aligned, the multi-line ones are not
+	var lines []string
+	for start := 0; start < len(fields); {
+		if strings.Contains(fields[start][1], "\n") {
+			lines = append(lines, fields[start][0]+": "+fields[start][1]+",")
+			start++
+			continue
+		}
+		end, width := start, 0
+		for ; end < len(fields) && !strings.Contains(fields[end][1], "\n"); end++ {
+			if len(fields[end][0]) > width {
+				width = len(fields[end][0])
+			}
+		}
+		for _, f := range fields[start:end] {
+			lines = append(lines, fmt.Sprintf("%-*s %s,", width+1, f[0]+":", f[1]))
+		}
+		start = end
+	}
+	return lines
+}
+
+// stringLiteral returns the Go literal of s, a raw string literal for a
+// multi-line string if possible.
+func stringLiteral(s string) string {
+	if strings.Contains(s, "\n") && !strings.Contains(s, "`") && !strings.Contains(s, "\r") {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
+// CmdFlags returns the statements defining the flags of the command.
+func (c *Command) CmdFlags() []string {
+	var statements []string
+	for _, f := range c.Flags {
+		s, _ := f.statements(c.CmdVar())
+		statements = append(statements, s...)
+	}
+	return statements
+}
+
+// ImportsTime reports whether the declaration of the command needs the time
+// package.
+func (c *Command) ImportsTime() bool {
+	for _, f := range c.Flags {
+		if f.Type == "duration" && f.Default != "" {
+			if d, err := time.ParseDuration(f.Default); err == nil && d != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validateDeclaration checks the args policy and the flags of the command.
+func (c *Command) validateDeclaration() error {
+	if _, err := argsExpression(c.Args); err != nil {
+		return fmt.Errorf("command %s: %v", c.CmdPath, err)
+	}
+	for _, f := range c.Flags {
+		if _, err := f.statements(c.CmdVar()); err != nil {
+			return fmt.Errorf("command %s: %v", c.CmdPath, err)
+		}
+	}
+	return nil
+}
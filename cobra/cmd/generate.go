@@ -0,0 +1,119 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	specPath          string
+	specPackageLayout bool
+
+	generateCmd = &cobra.Command{
+		Use:   "generate --spec <file>",
+		Short: "Generate the commands described by a spec file",
+		Long: `Generate (cobra generate) will create the commands described by a
+spec file, with a license and the appropriate structure for a
+Cobra-based CLI application, like cobra add.
+
+The spec file is a YAML or JSON description of the tree of commands,
+with the keys of the documentation generated by doc.GenYaml, and the
+aliases, args policy, valid args, types, persistence and requirement of
+the options and the subcommands in addition.  The spec may also be the
+directory of the files generated by doc.GenYamlTree.
+
+The root command of the spec is the root command of the application,
+which must have been created with cobra init, and whose options are to be
+declared in cmd/root.go.  The commands whose files
+already exist are left untouched, so that the newly described commands
+are added by running cobra generate again.
+
+Example spec:
+
+  name: app
+  commands:
+    - name: user
+      synopsis: Manage the users
+      commands:
+        - name: create
+          usage: create <name> [flags]
+          synopsis: Create a user
+          aliases: [new]
+          args: exact:1
+          options:
+            - name: admin
+              type: bool
+              usage: grant the admin role
+            - name: team
+              shorthand: t
+              default_value: core
+              required: true`,
+
+		Run: func(cmd *cobra.Command, args []string) {
+			wd, err := os.Getwd()
+			if err != nil {
+				er(err)
+			}
+			if err := generateCommands(wd, specPath, specPackageLayout, cmd.OutOrStdout()); err != nil {
+				er(err)
+			}
+		},
+	}
+)
+
+func init() {
+	generateCmd.Flags().StringVar(&specPath, "spec", "", "spec file or directory describing the commands")
+	generateCmd.Flags().BoolVar(&specPackageLayout, "package-layout", false, "place each command in its own package")
+	generateCmd.MarkFlagRequired("spec")
+}
+
+// generateCommands creates in the project in dir the commands described by
+// the spec at path which don't exist yet, and reports them to w.
+func generateCommands(dir, path string, packageLayout bool, w io.Writer) error {
+	spec, err := readSpec(path)
+	if err != nil {
+		return err
+	}
+	commands, err := specCommands(spec, packageLayout)
+	if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+
+	project := &Project{
+		AbsolutePath: dir,
+		PkgName:      modulePath(dir),
+		Legal:        projectLicense(dir),
+		Copyright:    copyrightLine(),
+		TemplateDir:  setting("template-dir", "templateDir", ""),
+		Vars:         templateVars(),
+	}
+	for _, command := range commands {
+		command.Project = project
+		err := command.Create()
+		if _, ok := err.(existsError); ok {
+			fmt.Fprintf(w, "%s already exists, left untouched\n", command.CmdPath)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s created\n", command.CmdPath)
+	}
+	return nil
+}
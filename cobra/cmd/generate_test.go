@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// generateProject creates the test project and the commands of the spec at
+// path, and returns the project and the report of cobra generate.
+func generateProject(t *testing.T, path string, packageLayout bool) (*Project, string) {
+	cfg = config{"year": "2020"}
+	defer func() { cfg = config{} }()
+
+	project := getProject()
+	project.Copyright = copyrightLine()
+	project.GoMod = packageLayout
+	if err := project.Create(); err != nil {
+		os.RemoveAll(project.AbsolutePath)
+		t.Fatal(err)
+	}
+	output := new(bytes.Buffer)
+	if err := generateCommands(project.AbsolutePath, path, packageLayout, output); err != nil {
+		os.RemoveAll(project.AbsolutePath)
+		t.Fatal(err)
+	}
+	return project, output.String()
+}
+
+func TestGoldenGenerateCmd(t *testing.T) {
+	project, output := generateProject(t, "testdata/spec/cli.yaml", false)
+	defer os.RemoveAll(project.AbsolutePath)
+
+	if output != "user created\nuser/create created\n" {
+		t.Errorf("Unexpected output %q", output)
+	}
+	generatedFile := filepath.Join(project.AbsolutePath, "cmd", "userCreate.go")
+	if err := compareFiles(generatedFile, "testdata/spec/userCreate.go.golden"); err != nil {
+		t.Fatal(err)
+	}
+	checkFileContains(t, filepath.Join(project.AbsolutePath, "cmd", "user.go"),
+		`userCmd.PersistentFlags().BoolP("verbose", "v", false, "print the details")`)
+}
+
+func TestGenerateCmdIdempotent(t *testing.T) {
+	project, _ := generateProject(t, "testdata/spec/cli.yaml", false)
+	defer os.RemoveAll(project.AbsolutePath)
+
+	userFile := filepath.Join(project.AbsolutePath, "cmd", "user.go")
+	if err := ioutil.WriteFile(userFile, []byte("package cmd\n\nvar userCmd = newUserCmd()\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := ioutil.ReadFile("testdata/spec/cli.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	specFile := filepath.Join(project.AbsolutePath, "cli.yaml")
+	spec = append(spec, []byte(`      - name: delete
+        synopsis: Delete a user
+        args: exact:1
+`)...)
+	if err := ioutil.WriteFile(specFile, spec, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	output := new(bytes.Buffer)
+	if err := generateCommands(project.AbsolutePath, specFile, false, output); err != nil {
+		t.Fatal(err)
+	}
+	expected := "user already exists, left untouched\n" +
+		"user/create already exists, left untouched\n" +
+		"user/delete created\n"
+	if output.String() != expected {
+		t.Errorf("Expected output %q, got %q", expected, output.String())
+	}
+	checkFileContent(t, userFile, "package cmd\n\nvar userCmd = newUserCmd()\n")
+	checkFileContains(t, filepath.Join(project.AbsolutePath, "cmd", "userDelete.go"), "\tArgs: cobra.ExactArgs(1),")
+}
+
+func TestGenerateCmdFromYamlTree(t *testing.T) {
+	// The spec of testdata/spec_tree is generated by doc.GenYamlTree
+	project, output := generateProject(t, "testdata/spec_tree", true)
+	defer os.RemoveAll(project.AbsolutePath)
+
+	if output != "server created\nserver/start created\n" {
+		t.Errorf("Unexpected output %q", output)
+	}
+	checkFileContains(t, filepath.Join(project.AbsolutePath, "cmd", "server", "server.go"),
+		`Cmd.PersistentFlags().StringP("address", "a", "localhost", "address of the server")`)
+
+	startFile := filepath.Join(project.AbsolutePath, "cmd", "server", "start", "start.go")
+	for _, expected := range []string{
+		`Use:   "start [name]",`,
+		`Short: "Start the server",`,
+		"Long: `Start the server and wait\nfor its shutdown.`,",
+		`Example: "app server start --port 8080",`,
+		`Cmd.Flags().StringSlice("origins", []string{"a.com", "b.com"}, "allowed origins")`,
+		`Cmd.Flags().IntP("port", "p", 8080, "port to listen on")`,
+		`Cmd.Flags().Duration("timeout", 30*time.Second, "timeout of the requests")`,
+		`Cmd.Flags().Bool("tls", false, "serve with TLS")`,
+	} {
+		checkFileContains(t, startFile, expected)
+	}
+	content, _ := ioutil.ReadFile(startFile)
+	if strings.Contains(string(content), `"help"`) {
+		t.Error("Expected the help flag to be left out")
+	}
+}
+
+func TestGenerateCmdErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cobra-spec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	testCases := map[string]string{
+		"args.yaml":    "name: app\ncommands:\n  - name: run\n    args: between:1,2\n",
+		"type.json":    `{"name": "app", "commands": [{"name": "run", "options": [{"name": "n", "type": "complex"}]}]}`,
+		"default.yaml": "name: app\ncommands:\n  - name: run\n    options:\n      - name: n\n        type: int\n        default_value: ten\n",
+		"usage.yaml":   "name: app\ncommands:\n  - name: run\n    usage: app start [flags]\n",
+		"twice.yaml":   "name: app\ncommands:\n  - name: run\n  - name: run\n",
+		"root.yaml":    "name: app\noptions:\n  - name: verbose\n    type: bool\ncommands:\n  - name: run\n",
+		"range.yaml":   "name: app\ncommands:\n  - name: run\n    args: range:3,1\n",
+	}
+	expected := map[string]string{
+		"args.yaml":    `command run: unknown args policy "between:1,2"`,
+		"type.json":    `command run: flag "n": unknown type "complex"`,
+		"default.yaml": `command run: flag "n": invalid default value "ten"`,
+		"usage.yaml":   `command "app run": usage "app start [flags]" doesn't start with the name of the command`,
+		"twice.yaml":   `command "app run" is described twice`,
+		"root.yaml":    `root.yaml: option "verbose" of the root command "app" isn't generated, declare it in cmd/root.go`,
+		"range.yaml":   `command run: invalid args policy "range:3,1": the minimum is greater than the maximum`,
+	}
+	for name, content := range testCases {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		err := generateCommands(dir, path, false, ioutil.Discard)
+		if err == nil || !strings.Contains(err.Error(), expected[name]) {
+			t.Errorf("%s: expected error %q, got %v", name, expected[name], err)
+		}
+	}
+}
+
+// checkFileContains checks that the file at path contains expected.
+func checkFileContains(t *testing.T, path, expected string) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), expected) {
+		t.Errorf("Expected %s to contain %q, got %q", path, expected, content)
+	}
+}
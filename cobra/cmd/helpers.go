@@ -14,8 +14,12 @@
 package cmd
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"io"
 	"os"
 	"os/exec"
@@ -110,6 +114,63 @@ func exists(path string) bool {
 	return false
 }
 
+// existsError reports a file which already exists and is not overwritten.
+type existsError string
+
+func (path existsError) Error() string {
+	return string(path) + " already exists, use --force to overwrite it"
+}
+
+// hasPackageVar checks if the Go package in dir declares the package-level
+// variable name.  The test files are ignored.
+func hasPackageVar(dir, name string) (bool, error) {
+	notTest := func(fi os.FileInfo) bool { return !strings.HasSuffix(fi.Name(), "_test.go") }
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, notTest, 0)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				genDecl, ok := decl.(*ast.GenDecl)
+				if !ok || genDecl.Tok != token.VAR {
+					continue
+				}
+				for _, spec := range genDecl.Specs {
+					for _, ident := range spec.(*ast.ValueSpec).Names {
+						if ident.Name == name {
+							return true, nil
+						}
+					}
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+// modulePath returns the module path declared in the go.mod file of dir,
+// or an empty string if dir has no go.mod file.
+func modulePath(dir string) string {
+	file, err := os.Open(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`)
+		}
+	}
+	return ""
+}
+
 func executeTemplate(tmplStr string, data interface{}) (string, error) {
 	tmpl, err := template.New("").Funcs(template.FuncMap{"comment": commentifyString}).Parse(tmplStr)
 	if err != nil {
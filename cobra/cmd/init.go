@@ -19,11 +19,14 @@ import (
 	"path"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var (
-	pkgName string
+	pkgName    string
+	goMod      bool
+	rootTest   bool
+	genDocs    bool
+	completion bool
 
 	initCmd = &cobra.Command{
 		Use:     "init [name]",
@@ -34,6 +37,9 @@ and the appropriate structure for a Cobra-based CLI application.
 
   * If a name is provided, a directory with that name will be created in the current directory;
   * If no name is provided, the current directory will be assumed;
+
+Options add a go.mod file, a test of the root command, a hidden
+gen-docs command generating the documentation and a completion command.
 `,
 
 		Run: func(_ *cobra.Command, args []string) {
@@ -43,12 +49,19 @@ and the appropriate structure for a Cobra-based CLI application.
 				er(err)
 			}
 			fmt.Printf("Your Cobra application is ready at\n%s\n", projectPath)
+			if goMod {
+				fmt.Println("Run 'go mod tidy' in it to complete the requirements of go.mod")
+			}
 		},
 	}
 )
 
 func init() {
 	initCmd.Flags().StringVar(&pkgName, "pkg-name", "", "fully qualified pkg name")
+	initCmd.Flags().BoolVar(&goMod, "go-mod", false, "create the go.mod file of the module pkg-name")
+	initCmd.Flags().BoolVar(&rootTest, "root-test", false, "create a test of the root command")
+	initCmd.Flags().BoolVar(&genDocs, "gen-docs", false, "add a hidden gen-docs command generating the documentation")
+	initCmd.Flags().BoolVar(&completion, "completion", false, "add a completion command generating the shell completion scripts")
 	initCmd.MarkFlagRequired("pkg-name")
 }
 
@@ -69,8 +82,14 @@ func initializeProject(args []string) (string, error) {
 		PkgName:      pkgName,
 		Legal:        getLicense(),
 		Copyright:    copyrightLine(),
-		Viper:        viper.GetBool("useViper"),
+		Viper:        boolSetting("viper", "useViper", true),
 		AppName:      path.Base(pkgName),
+		TemplateDir:  setting("template-dir", "templateDir", ""),
+		Vars:         templateVars(),
+		GoMod:        goMod,
+		RootTest:     rootTest,
+		GenDocs:      genDocs,
+		Completion:   completion,
 	}
 
 	if err := project.Create(); err != nil {
@@ -6,8 +6,6 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
-
-	"github.com/spf13/viper"
 )
 
 func getProject() *Project {
@@ -60,7 +58,7 @@ func TestGoldenInitCmd(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 
 			initCmd.Flags().Set("pkg-name", tt.pkgName)
-			viper.Set("useViper", true)
+			rootCmd.PersistentFlags().Set("viper", "true")
 			projectPath, err := initializeProject(tt.args)
 			defer func() {
 				if projectPath != "" {
@@ -92,3 +90,35 @@ func TestGoldenInitCmd(t *testing.T) {
 		})
 	}
 }
+
+func TestGoldenInitCmdOptions(t *testing.T) {
+	project := getProject()
+	project.Copyright = "Copyright © 2020 NAME HERE <EMAIL ADDRESS>"
+	project.GoMod = true
+	project.RootTest = true
+	project.GenDocs = true
+	project.Completion = true
+	defer os.RemoveAll(project.AbsolutePath)
+
+	if err := project.Create(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedFiles := []string{"go.mod", "cmd/root_test.go", "cmd/gen_docs.go", "cmd/completion.go"}
+	for _, f := range expectedFiles {
+		generatedFile := fmt.Sprintf("%s/%s", project.AbsolutePath, f)
+		goldenFile := fmt.Sprintf("testdata/%s.golden", filepath.Base(f))
+		if err := compareFiles(generatedFile, goldenFile); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Without Viper, go.mod only requires Cobra
+	project.Viper = false
+	if err := project.Create(); err != nil {
+		t.Fatal(err)
+	}
+	if err := compareFiles(project.AbsolutePath+"/go.mod", "testdata/go.mod.noviper.golden"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,8 +1,9 @@
 package cmd
 
 func initAgpl() {
-	Licenses["agpl"] = License{
+	Licenses["AGPL-3.0-or-later"] = License{
 		Name:            "GNU Affero General Public License",
+		SPDX:            "AGPL-3.0-or-later",
 		PossibleMatches: []string{"agpl", "affero gpl", "gnu agpl"},
 		Header: `
 This program is free software: you can redistribute it and/or modify
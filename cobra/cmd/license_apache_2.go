@@ -16,8 +16,9 @@
 package cmd
 
 func initApache2() {
-	Licenses["apache"] = License{
+	Licenses["Apache-2.0"] = License{
 		Name:            "Apache 2.0",
+		SPDX:            "Apache-2.0",
 		PossibleMatches: []string{"apache", "apache20", "apache 2.0", "apache2.0", "apache-2.0"},
 		Header: `
 Licensed under the Apache License, Version 2.0 (the "License");
@@ -16,8 +16,9 @@
 package cmd
 
 func initBsdClause2() {
-	Licenses["freebsd"] = License{
+	Licenses["BSD-2-Clause"] = License{
 		Name: "Simplified BSD License",
+		SPDX: "BSD-2-Clause",
 		PossibleMatches: []string{"freebsd", "simpbsd", "simple bsd", "2-clause bsd",
 			"2 clause bsd", "simplified bsd license"},
 		Header: `All rights reserved.
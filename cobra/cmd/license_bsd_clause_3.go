@@ -16,8 +16,9 @@
 package cmd
 
 func initBsdClause3() {
-	Licenses["bsd"] = License{
+	Licenses["BSD-3-Clause"] = License{
 		Name:            "NewBSD",
+		SPDX:            "BSD-3-Clause",
 		PossibleMatches: []string{"bsd", "newbsd", "3 clause bsd", "3-clause bsd"},
 		Header: `All rights reserved.
 
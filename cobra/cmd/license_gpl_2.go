@@ -16,8 +16,9 @@
 package cmd
 
 func initGpl2() {
-	Licenses["gpl2"] = License{
+	Licenses["GPL-2.0-or-later"] = License{
 		Name:            "GNU General Public License 2.0",
+		SPDX:            "GPL-2.0-or-later",
 		PossibleMatches: []string{"gpl2", "gnu gpl2", "gplv2"},
 		Header: `
 This program is free software; you can redistribute it and/or
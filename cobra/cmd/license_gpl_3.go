@@ -16,8 +16,9 @@
 package cmd
 
 func initGpl3() {
-	Licenses["gpl3"] = License{
+	Licenses["GPL-3.0-or-later"] = License{
 		Name:            "GNU General Public License 3.0",
+		SPDX:            "GPL-3.0-or-later",
 		PossibleMatches: []string{"gpl3", "gplv3", "gpl", "gnu gpl3", "gnu gpl"},
 		Header: `
 This program is free software: you can redistribute it and/or modify
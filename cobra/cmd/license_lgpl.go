@@ -1,8 +1,9 @@
 package cmd
 
 func initLgpl() {
-	Licenses["lgpl"] = License{
+	Licenses["LGPL-3.0-or-later"] = License{
 		Name:            "GNU Lesser General Public License",
+		SPDX:            "LGPL-3.0-or-later",
 		PossibleMatches: []string{"lgpl", "lesser gpl", "gnu lgpl"},
 		Header: `
 This program is free software: you can redistribute it and/or modify
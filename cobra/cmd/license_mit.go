@@ -16,8 +16,9 @@
 package cmd
 
 func initMit() {
-	Licenses["mit"] = License{
+	Licenses["MIT"] = License{
 		Name:            "MIT License",
+		SPDX:            "MIT",
 		PossibleMatches: []string{"mit"},
 		Header: `
 Permission is hereby granted, free of charge, to any person obtaining a copy
@@ -16,21 +16,27 @@
 package cmd
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
-
-	"github.com/spf13/viper"
+	"unicode"
 )
 
-// Licenses contains all possible licenses a user can choose from.
+// Licenses contains all possible licenses a user can choose from, by SPDX
+// identifier.
 var Licenses = make(map[string]License)
 
 // License represents a software license agreement, containing the Name of
-// the license, its possible matches (on the command line as given to cobra),
-// the header to be used with each file on the file's creating, and the text
-// of the license
+// the license, its SPDX identifier, its possible matches (on the command line
+// as given to cobra), the header to be used with each file on the file's
+// creating, and the text of the license
 type License struct {
 	Name            string   // The type of license in use
+	SPDX            string   // SPDX license identifier
 	PossibleMatches []string // Similar names to guess
 	Text            string   // License text data
 	Header          string   // License header for source files
@@ -38,7 +44,7 @@ type License struct {
 
 func init() {
 	// Allows a user to not use a license.
-	Licenses["none"] = License{"None", []string{"none", "false"}, "", ""}
+	Licenses["none"] = License{Name: "None", PossibleMatches: []string{"none", "false"}}
 
 	initApache2()
 	initMit()
@@ -48,12 +54,14 @@ func init() {
 	initGpl3()
 	initLgpl()
 	initAgpl()
+	initMpl2()
+	initIsc()
+	initUnlicense()
+	initEpl2()
 }
 
 // getLicense returns license specified by user in flag or in config.
 // If user didn't specify the license, it returns Apache License 2.0.
-//
-// TODO: Inspect project for existing license
 func getLicense() License {
 	// If explicitly flagged, use that.
 	if userLicense != "" {
@@ -61,24 +69,25 @@ func getLicense() License {
 	}
 
 	// If user wants to have custom license, use that.
-	if viper.IsSet("license.header") || viper.IsSet("license.text") {
-		return License{Header: viper.GetString("license.header"),
-			Text: viper.GetString("license.text")}
+	if cfg.isSet("license.header") || cfg.isSet("license.text") {
+		return License{SPDX: cfg.getString("license.spdx"),
+			Header: cfg.getString("license.header"),
+			Text:   cfg.getString("license.text")}
 	}
 
 	// If user wants to have built-in license, use that.
-	if viper.IsSet("license") {
-		return findLicense(viper.GetString("license"))
+	if cfg.isSet("license") {
+		return findLicense(cfg.getString("license"))
 	}
 
 	// If user didn't set any license, use Apache 2.0 by default.
-	return Licenses["apache"]
+	return Licenses["Apache-2.0"]
 }
 
 func copyrightLine() string {
-	author := viper.GetString("author")
+	author := setting("author", "author", "NAME HERE <EMAIL ADDRESS>")
 
-	year := viper.GetString("year") // For tests.
+	year := cfg.getString("year") // For tests.
 	if year == "" {
 		year = time.Now().Format("2006")
 	}
@@ -97,8 +106,8 @@ func findLicense(name string) License {
 	return Licenses[found]
 }
 
-// matchLicense compares the given a license name
-// to PossibleMatches of all built-in licenses.
+// matchLicense compares the given a license name to the SPDX identifiers
+// and PossibleMatches of all built-in licenses.
 // It returns blank string, if name is blank string or it didn't find
 // then appropriate match to name.
 func matchLicense(name string) string {
@@ -107,6 +116,9 @@ func matchLicense(name string) string {
 	}
 
 	for key, lic := range Licenses {
+		if strings.EqualFold(name, key) {
+			return key
+		}
 		for _, match := range lic.PossibleMatches {
 			if strings.EqualFold(name, match) {
 				return key
@@ -116,3 +128,163 @@ func matchLicense(name string) string {
 
 	return ""
 }
+
+// projectLicense returns the license of the new files of the project in dir.
+// Unless the user specified the license in flag, it is the license detected
+// in the project, so that the new files match the existing ones.
+func projectLicense(dir string) License {
+	if userLicense == "" {
+		if lic, ok := detectLicense(dir); ok {
+			return lic
+		}
+	}
+	return getLicense()
+}
+
+// licenseFileNames are the names of the license file of a project.
+var licenseFileNames = []string{"LICENSE", "LICENSE.txt", "LICENSE.md", "COPYING"}
+
+// spdxPattern matches the SPDX license identifier of a file header.
+var spdxPattern = regexp.MustCompile(`SPDX-License-Identifier:\s*(\S+)`)
+
+// licenseTextSimilarity is the minimum similarity of the license file of a
+// project to the text of a built-in license for the license to be detected.
+const licenseTextSimilarity = 0.75
+
+// detectLicense returns the built-in license of the project in dir, detected
+// from the SPDX license identifier or the license header of main.go and of the
+// files of the cmd directory, or else from the license file of the project.
+func detectLicense(dir string) (License, bool) {
+	for _, header := range sourceHeaders(dir) {
+		if m := spdxPattern.FindStringSubmatch(header); m != nil {
+			if key := matchLicense(m[1]); key != "" {
+				return Licenses[key], true
+			}
+		}
+		if key := matchLicenseHeader(header); key != "" {
+			return Licenses[key], true
+		}
+	}
+
+	for _, name := range licenseFileNames {
+		text, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		if key := matchLicenseText(string(text)); key != "" {
+			return Licenses[key], true
+		}
+		break
+	}
+	return License{}, false
+}
+
+// sourceHeaders returns the comments preceding the package clause of main.go
+// and of the Go files of the cmd directory of the project in dir.
+func sourceHeaders(dir string) []string {
+	paths := []string{filepath.Join(dir, "main.go")}
+	filepath.Walk(filepath.Join(dir, "cmd"), func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() && strings.HasSuffix(path, ".go") {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+
+	var headers []string
+	for _, path := range paths {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		header := string(content)
+		if i := strings.Index(header, "\npackage "); i >= 0 {
+			header = header[:i]
+		}
+		headers = append(headers, header)
+	}
+	return headers
+}
+
+// matchLicenseHeader returns the key of the built-in license whose header is
+// contained in the given file header, preferring the longest one, or blank
+// string if there is none.
+func matchLicenseHeader(header string) string {
+	header = strings.Join(licenseWords(header), " ")
+	found, length := "", 0
+	for key, lic := range Licenses {
+		words := strings.Join(licenseWords(lic.Header), " ")
+		if words != "" && len(words) > length && strings.Contains(header, words) {
+			found, length = key, len(words)
+		}
+	}
+	return found
+}
+
+// matchLicenseText returns the key of the built-in license whose text is the
+// most similar to the given one, or blank string if none is similar enough.
+func matchLicenseText(text string) string {
+	trigrams := licenseTrigrams(text)
+	keys := make([]string, 0, len(Licenses))
+	for key := range Licenses {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	found, best := "", licenseTextSimilarity
+	for _, key := range keys {
+		if Licenses[key].Text == "" {
+			continue
+		}
+		if s := similarity(trigrams, licenseTrigrams(Licenses[key].Text)); s >= best {
+			found, best = key, s
+		}
+	}
+	return found
+}
+
+// templateActionPattern matches the template actions of a license text.
+var templateActionPattern = regexp.MustCompile(`{{[^}]*}}`)
+
+// licenseWords returns the lowercased words of a license text or header,
+// leaving out the template actions, the copyright lines and the comment
+// markers.
+func licenseWords(text string) []string {
+	text = templateActionPattern.ReplaceAllString(text, "")
+	var words []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.ToLower(line)
+		if strings.HasPrefix(strings.TrimLeft(line, "/*# \t"), "copyright") {
+			continue
+		}
+		words = append(words, strings.FieldsFunc(line, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})...)
+	}
+	return words
+}
+
+// licenseTrigrams returns the set of the sequences of three words of a
+// license text.
+func licenseTrigrams(text string) map[string]bool {
+	words := licenseWords(text)
+	trigrams := make(map[string]bool)
+	for i := 0; i+3 <= len(words); i++ {
+		trigrams[strings.Join(words[i:i+3], " ")] = true
+	}
+	return trigrams
+}
+
+// similarity returns the Jaccard index of two sets.
+func similarity(a, b map[string]bool) float64 {
+	common := 0
+	for s := range a {
+		if b[s] {
+			common++
+		}
+	}
+	union := len(a) + len(b) - common
+	if union == 0 {
+		return 0
+	}
+	return float64(common) / float64(union)
+}
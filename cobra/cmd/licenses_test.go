@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestMatchLicense(t *testing.T) {
+	tests := map[string]string{
+		"MIT":              "MIT",
+		"apache-2.0":       "Apache-2.0",
+		"apache":           "Apache-2.0",
+		"gpl3":             "GPL-3.0-or-later",
+		"GPL-2.0-or-later": "GPL-2.0-or-later",
+		"2-clause bsd":     "BSD-2-Clause",
+		"mpl-2.0":          "MPL-2.0",
+		"isc":              "ISC",
+		"Unlicense":        "Unlicense",
+		"eclipse":          "EPL-2.0",
+		"none":             "none",
+		"":                 "",
+		"unknown license":  "",
+	}
+	for name, expected := range tests {
+		if got := matchLicense(name); got != expected {
+			t.Errorf("Expected %q to match %q, got %q", name, expected, got)
+		}
+	}
+
+	for key, lic := range Licenses {
+		if key != "none" && lic.SPDX != key {
+			t.Errorf("Expected the license %q to have the SPDX identifier %q, got %q", lic.Name, key, lic.SPDX)
+		}
+	}
+}
+
+func TestDetectLicense(t *testing.T) {
+	var keys []string
+	for key, lic := range Licenses {
+		if lic.Text != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		t.Run(key, func(t *testing.T) {
+			project := getProject()
+			project.Legal = Licenses[key]
+			defer os.RemoveAll(project.AbsolutePath)
+			if err := project.Create(); err != nil {
+				t.Fatal(err)
+			}
+
+			// From the SPDX license identifier
+			if lic, ok := detectLicense(project.AbsolutePath); !ok || lic.SPDX != key {
+				t.Errorf("Expected %q from the SPDX license identifier, got %q", key, lic.SPDX)
+			}
+
+			// From the license header
+			if Licenses[key].Header != "" {
+				removeSPDXLines(t, filepath.Join(project.AbsolutePath, "main.go"))
+				removeSPDXLines(t, filepath.Join(project.AbsolutePath, "cmd", "root.go"))
+				if lic, ok := detectLicense(project.AbsolutePath); !ok || lic.SPDX != key {
+					t.Errorf("Expected %q from the license header, got %q", key, lic.SPDX)
+				}
+			}
+
+			// From the LICENSE file
+			os.Remove(filepath.Join(project.AbsolutePath, "main.go"))
+			os.RemoveAll(filepath.Join(project.AbsolutePath, "cmd"))
+			if lic, ok := detectLicense(project.AbsolutePath); !ok || lic.SPDX != key {
+				t.Errorf("Expected %q from the LICENSE file, got %q", key, lic.SPDX)
+			}
+		})
+	}
+}
+
+// removeSPDXLines removes the SPDX license identifier lines of a file.
+func removeSPDXLines(t *testing.T, path string) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if !strings.HasPrefix(line, "SPDX-License-Identifier:") {
+			lines = append(lines, line)
+		}
+	}
+	if err := ioutil.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProjectLicense(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cobra-license")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Without license, the default one is used
+	if got := projectLicense(dir); got.SPDX != "Apache-2.0" {
+		t.Errorf("Expected the default license, got %q", got.SPDX)
+	}
+
+	// The copyright line and the formatting of the file don't matter
+	text := strings.Replace(Licenses["MIT"].Text, "{{ .copyright }}", "Copyright (c) 2016 Someone Else", 1)
+	text = strings.Replace(text, "\n", "\n\n", -1)
+	if err := ioutil.WriteFile(filepath.Join(dir, "LICENSE.md"), []byte("# "+text), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := projectLicense(dir); got.SPDX != "MIT" {
+		t.Errorf("Expected the license of the LICENSE file, got %q", got.SPDX)
+	}
+
+	// The license given in flag takes precedence
+	userLicense = "isc"
+	defer func() { userLicense = "" }()
+	if got := projectLicense(dir); got.SPDX != "ISC" {
+		t.Errorf("Expected the license of the flag, got %q", got.SPDX)
+	}
+}
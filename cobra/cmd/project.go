@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path"
+	"path/filepath"
+	"strings"
 	"text/template"
 
 	"github.com/spf13/cobra/cobra/tpl"
@@ -17,11 +21,47 @@ type Project struct {
 	Legal        License
 	Viper        bool
 	AppName      string
+	// TemplateDir is the directory of the templates replacing the built-in
+	// templates of cobra init and cobra add.
+	TemplateDir string
+	// Vars are the user-defined variables of the templates.
+	Vars map[string]string
+	// GoMod, RootTest, GenDocs and Completion add the go.mod file, the test
+	// of the root command, the hidden gen-docs command and the completion
+	// command to the built-in templates of cobra init.
+	GoMod      bool
+	RootTest   bool
+	GenDocs    bool
+	Completion bool
 }
 
+// Command contains the name, parent and layout of a command to add.
 type Command struct {
-	CmdName   string
+	// CmdName is the identifier of the command, e.g. "userCreate" for the
+	// variable userCreateCmd in cmd/userCreate.go.
+	CmdName string
+	// CmdPath is the path of the command from the root command, e.g.
+	// "user/create".  It defaults to CmdName.
+	CmdPath string
+	// CmdParent is the variable name of the parent command.
 	CmdParent string
+	// PackageLayout places the command in its own package, e.g.
+	// cmd/user/create for "user/create", registered in the package of its
+	// parent.
+	PackageLayout bool
+	// Force allows overwriting existing files.
+	Force bool
+	// Use, Short, Long, Example, Aliases, ValidArgs and Flags declare the
+	// command, by default with placeholders.  Args is the name of its
+	// positional arguments policy, e.g. "exact:2".
+	Use       string
+	Short     string
+	Long      string
+	Example   string
+	Aliases   []string
+	Args      string
+	ValidArgs []string
+	Flags     []Flag
 	*Project
 }
 
@@ -34,33 +74,22 @@ func (p *Project) Create() error {
 		}
 	}
 
-	// create main.go
-	mainFile, err := os.Create(fmt.Sprintf("%s/main.go", p.AbsolutePath))
-	if err != nil {
-		return err
-	}
-	defer mainFile.Close()
-
-	mainTemplate := template.Must(template.New("main").Parse(string(tpl.MainTemplate())))
-	err = mainTemplate.Execute(mainFile, p)
-	if err != nil {
-		return err
-	}
-
-	// create cmd/root.go
-	if _, err = os.Stat(fmt.Sprintf("%s/cmd", p.AbsolutePath)); os.IsNotExist(err) {
-		os.Mkdir(fmt.Sprintf("%s/cmd", p.AbsolutePath), 0751)
+	// create main.go, cmd/root.go and the other files of the template set
+	templates := builtinInitTemplates(p)
+	if p.TemplateDir != "" {
+		custom, err := loadTemplateDir(p.TemplateDir, "init")
+		if err != nil {
+			return err
+		}
+		if custom != nil {
+			templates = custom
+		}
 	}
-	rootFile, err := os.Create(fmt.Sprintf("%s/cmd/root.go", p.AbsolutePath))
-	if err != nil {
+	if err := templates.render(p.AbsolutePath, p, true); err != nil {
 		return err
 	}
-	defer rootFile.Close()
-
-	rootTemplate := template.Must(template.New("root").Parse(string(tpl.RootTemplate())))
-	err = rootTemplate.Execute(rootFile, p)
-	if err != nil {
-		return err
+	if _, ok := templates["LICENSE"]; ok {
+		return nil
 	}
 
 	// create license
@@ -82,16 +111,130 @@ func (p *Project) createLicenseFile() error {
 }
 
 func (c *Command) Create() error {
-	cmdFile, err := os.Create(fmt.Sprintf("%s/cmd/%s.go", c.AbsolutePath, c.CmdName))
-	if err != nil {
+	if err := c.validateDeclaration(); err != nil {
 		return err
 	}
-	defer cmdFile.Close()
 
-	commandTemplate := template.Must(template.New("sub").Parse(string(tpl.AddCommandTemplate())))
-	err = commandTemplate.Execute(cmdFile, c)
+	parentDir := filepath.Join(c.AbsolutePath, "cmd", filepath.Join(c.parentSegments()...))
+	found, err := hasPackageVar(parentDir, c.CmdParent)
 	if err != nil {
 		return err
 	}
+	if !found {
+		return fmt.Errorf("parent command variable %q not found in %s", c.CmdParent, parentDir)
+	}
+
+	if c.TemplateDir != "" {
+		templates, err := loadTemplateDir(c.TemplateDir, "add")
+		if err != nil {
+			return err
+		}
+		if templates != nil {
+			return templates.render(c.AbsolutePath, c, c.Force)
+		}
+	}
+
+	cmdPath := filepath.Join(c.AbsolutePath, "cmd", c.CmdName+".go")
+	registrationPath := ""
+	if c.PackageLayout {
+		if c.PkgName == "" {
+			return errors.New("the package layout needs the module path of the project, found in its go.mod")
+		}
+		cmdPath = filepath.Join(c.AbsolutePath, "cmd", filepath.Join(c.packageSegments()...), c.CmdPackage()+".go")
+		registrationPath = filepath.Join(parentDir, c.CmdPackage()+".go")
+	}
+	for _, file := range []string{cmdPath, registrationPath} {
+		if file != "" && !c.Force && exists(file) {
+			return existsError(file)
+		}
+	}
+
+	if err := c.createFile(cmdPath, "sub", tpl.AddCommandTemplate()); err != nil {
+		return err
+	}
+	if registrationPath != "" {
+		return c.createFile(registrationPath, "registration", tpl.AddCommandRegistrationTemplate())
+	}
 	return nil
 }
+
+func (c *Command) createFile(path, name string, text []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0751); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	commandTemplate := template.Must(template.New(name).Parse(string(text)))
+	return commandTemplate.Execute(file, c)
+}
+
+// segments returns the names of the commands on the path of the command.
+func (c *Command) segments() []string {
+	if c.CmdPath == "" {
+		return []string{c.CmdName}
+	}
+	return strings.Split(c.CmdPath, "/")
+}
+
+// parentSegments returns the directories of the package of the parent
+// command under cmd, none for a parent in the cmd package.
+func (c *Command) parentSegments() []string {
+	if !c.PackageLayout {
+		return nil
+	}
+	segments := c.packageSegments()
+	return segments[:len(segments)-1]
+}
+
+// packageSegments returns the directories of the package of the command
+// under cmd with the package layout.
+func (c *Command) packageSegments() []string {
+	var segments []string
+	for _, s := range c.segments() {
+		segments = append(segments, strings.ToLower(s))
+	}
+	return segments
+}
+
+// CmdUse returns the name of the command on the command line.
+func (c *Command) CmdUse() string {
+	if fields := strings.Fields(c.Use); len(fields) > 0 {
+		return fields[0]
+	}
+	segments := c.segments()
+	return segments[len(segments)-1]
+}
+
+// CmdVar returns the variable name of the command.
+func (c *Command) CmdVar() string {
+	if c.PackageLayout {
+		return "Cmd"
+	}
+	return c.CmdName + "Cmd"
+}
+
+// CmdPackage returns the package name of the command.
+func (c *Command) CmdPackage() string {
+	if c.PackageLayout {
+		segments := c.packageSegments()
+		return segments[len(segments)-1]
+	}
+	return "cmd"
+}
+
+// ParentPackage returns the package name of the parent command.
+func (c *Command) ParentPackage() string {
+	if segments := c.parentSegments(); len(segments) > 0 {
+		return segments[len(segments)-1]
+	}
+	return "cmd"
+}
+
+// CmdImportPath returns the import path of the package of the command.
+func (c *Command) CmdImportPath() string {
+	return path.Join(append([]string{c.PkgName, "cmd"}, c.packageSegments()...)...)
+}
@@ -0,0 +1,284 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra/spec"
+	"gopkg.in/yaml.v2"
+)
+
+// commandSpec is the declarative description of a command in a spec file.
+// Its keys are the ones of the documentation generated by doc.GenYaml, with
+// the aliases, the args policy, the valid args, the types, persistence and
+// requirement of the options and the subcommands in addition.
+type commandSpec struct {
+	// Name is the name of the command, or its path from the root command as
+	// generated by doc.GenYaml, e.g. "app user create".
+	Name        string `yaml:"name" json:"name"`
+	Synopsis    string `yaml:"synopsis" json:"synopsis"`
+	Description string `yaml:"description" json:"description"`
+	// Usage is the use line of the command, as generated by doc.GenYaml,
+	// e.g. "app user create <name> [flags]".  It defaults to the name.
+	Usage     string       `yaml:"usage" json:"usage"`
+	Example   string       `yaml:"example" json:"example"`
+	Aliases   []string     `yaml:"aliases" json:"aliases"`
+	Args      string       `yaml:"args" json:"args"`
+	ValidArgs []string     `yaml:"valid_args" json:"valid_args"`
+	Options   []optionSpec `yaml:"options" json:"options"`
+	// InheritedOptions are the options inherited from the parent commands,
+	// which are declared persistent in the parent commands.
+	InheritedOptions []optionSpec   `yaml:"inherited_options" json:"inherited_options"`
+	Commands         []*commandSpec `yaml:"commands" json:"commands"`
+}
+
+// optionSpec is the declarative description of a flag in a spec file.
+type optionSpec struct {
+	Name         string `yaml:"name" json:"name"`
+	Shorthand    string `yaml:"shorthand" json:"shorthand"`
+	DefaultValue string `yaml:"default_value" json:"default_value"`
+	Usage        string `yaml:"usage" json:"usage"`
+	// Type is the type of the flag.  It is inferred from the default value
+	// if it is not set: bool for true or false, stringSlice for a list in
+	// brackets, int for an integer, duration for a duration and string
+	// otherwise.
+	Type       string `yaml:"type" json:"type"`
+	Persistent bool   `yaml:"persistent" json:"persistent"`
+	Required   bool   `yaml:"required" json:"required"`
+}
+
+// specExtensions are the extensions of the spec files of a spec directory.
+var specExtensions = []string{".yaml", ".yml", ".json"}
+
+// readSpec reads the spec of the root command from path: a YAML or JSON file
+// describing the command tree, or a directory of files describing a command
+// each, as generated by doc.GenYamlTree.
+func readSpec(path string) (*commandSpec, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return readSpecFile(path)
+	}
+
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var specs []*commandSpec
+	for _, f := range files {
+		if f.IsDir() || !hasSpecExtension(f.Name()) {
+			continue
+		}
+		spec, err := readSpecFile(filepath.Join(path, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	return specTree(path, specs)
+}
+
+// hasSpecExtension reports whether name has the extension of a spec file.
+func hasSpecExtension(name string) bool {
+	for _, ext := range specExtensions {
+		if filepath.Ext(name) == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// readSpecFile reads the spec of a command from a YAML or JSON file.
+func readSpecFile(path string) (*commandSpec, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	spec := &commandSpec{}
+	if filepath.Ext(path) == ".json" {
+		err = json.Unmarshal(content, spec)
+	} else {
+		err = yaml.Unmarshal(content, spec)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if strings.TrimSpace(spec.Name) == "" {
+		return nil, fmt.Errorf("%s: the command has no name", path)
+	}
+	return spec, nil
+}
+
+// specTree links the specs of the commands of a spec directory by their
+// paths into the tree of the root command.
+func specTree(dir string, specs []*commandSpec) (*commandSpec, error) {
+	byPath := make(map[string]*commandSpec, len(specs))
+	var root *commandSpec
+	for _, spec := range specs {
+		path := strings.Join(strings.Fields(spec.Name), " ")
+		if byPath[path] != nil {
+			return nil, fmt.Errorf("%s: command %q is described twice", dir, path)
+		}
+		byPath[path] = spec
+		if !strings.Contains(path, " ") {
+			if root != nil {
+				return nil, fmt.Errorf("%s: found the root commands %q and %q", dir, root.Name, spec.Name)
+			}
+			root = spec
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("%s: no root command found", dir)
+	}
+
+	paths := make([]string, 0, len(byPath))
+	for path := range byPath {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		i := strings.LastIndex(path, " ")
+		if i < 0 {
+			continue
+		}
+		parent := byPath[path[:i]]
+		if parent == nil {
+			return nil, fmt.Errorf("%s: parent command %q of %q not found", dir, path[:i], path)
+		}
+		parent.Commands = append(parent.Commands, byPath[path])
+	}
+	return root, nil
+}
+
+// specCommands returns the commands to add described by the subcommands of
+// the root spec, parents first.  The root command itself is the one of the
+// project, so the options of the root spec are rejected rather than dropped.
+func specCommands(root *commandSpec, packageLayout bool) ([]*Command, error) {
+	var commands []*Command
+	var walk func(spec *commandSpec, names []string, parentPath string) error
+	walk = func(spec *commandSpec, names []string, parentPath string) error {
+		seen := make(map[string]bool)
+		for _, sub := range spec.Commands {
+			fields := strings.Fields(sub.Name)
+			if len(fields) == 0 {
+				return fmt.Errorf("a subcommand of %q has no name", parentPath)
+			}
+			name := fields[len(fields)-1]
+			if seen[name] {
+				return fmt.Errorf("command %q is described twice", parentPath+" "+name)
+			}
+			seen[name] = true
+
+			path := append(append([]string{}, names...), name)
+			command, err := newCommand(strings.Join(path, "/"), "rootCmd", false, packageLayout)
+			if err != nil {
+				return err
+			}
+			if err := sub.declare(command, parentPath); err != nil {
+				return fmt.Errorf("command %q: %v", parentPath+" "+name, err)
+			}
+			if err := command.validateDeclaration(); err != nil {
+				return err
+			}
+			commands = append(commands, command)
+			if err := walk(sub, path, parentPath+" "+name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	fields := strings.Fields(root.Name)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("the root command has no name")
+	}
+	for _, o := range root.Options {
+		// The help flag is added to every command by cobra
+		if o.Name != "help" {
+			return nil, fmt.Errorf("option %q of the root command %q isn't generated, declare it in cmd/root.go", o.Name, root.Name)
+		}
+	}
+	if err := walk(root, nil, fields[len(fields)-1]); err != nil {
+		return nil, err
+	}
+	return commands, nil
+}
+
+// declare sets the declaration of the command from its spec.  The use line
+// is the usage without the path of the parent command and the flags
+// placeholder.
+func (s *commandSpec) declare(c *Command, parentPath string) error {
+	fields := strings.Fields(s.Name)
+	name := fields[len(fields)-1]
+
+	use := strings.TrimSpace(s.Usage)
+	use = strings.TrimSpace(strings.TrimSuffix(use, "[flags]"))
+	use = strings.TrimSpace(strings.TrimPrefix(use, parentPath+" "))
+	if use == "" {
+		use = name
+	}
+	if strings.Fields(use)[0] != name {
+		return fmt.Errorf("usage %q doesn't start with the name of the command", s.Usage)
+	}
+
+	c.Use = use
+	c.Short = strings.TrimSpace(s.Synopsis)
+	c.Long = strings.TrimSpace(s.Description)
+	c.Example = strings.TrimRight(s.Example, "\n")
+	c.Aliases = s.Aliases
+	c.Args = s.Args
+	c.ValidArgs = s.ValidArgs
+	for _, o := range s.Options {
+		if o.Name == "help" {
+			// Added to every command by cobra
+			continue
+		}
+		typ := o.Type
+		if typ == "" {
+			typ = spec.InferType(o.DefaultValue)
+		}
+		c.Flags = append(c.Flags, Flag{
+			Name:       o.Name,
+			Shorthand:  o.Shorthand,
+			Type:       typ,
+			Default:    strings.TrimSpace(o.DefaultValue),
+			Usage:      strings.TrimSpace(o.Usage),
+			Persistent: o.Persistent || s.inheritedBySubcommands(o.Name),
+			Required:   o.Required,
+		})
+	}
+	return nil
+}
+
+// inheritedBySubcommands reports whether a subcommand inherits the option
+// name of the command.
+func (s *commandSpec) inheritedBySubcommands(name string) bool {
+	for _, sub := range s.Commands {
+		for _, o := range sub.InheritedOptions {
+			if o.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra/cobra/tpl"
+)
+
+// templateSuffix is the suffix of the files of a template directory which
+// are rendered as templates.  The other files are copied as they are.
+const templateSuffix = ".tmpl"
+
+// templateSet maps the paths of the files to generate, relative to the
+// project and which may contain template actions, to the templates of their
+// content.
+type templateSet map[string]string
+
+// builtinInitTemplates returns the default templates of cobra init for the
+// options of the project.
+func builtinInitTemplates(p *Project) templateSet {
+	set := templateSet{
+		"main.go":     string(tpl.MainTemplate()),
+		"cmd/root.go": string(tpl.RootTemplate()),
+	}
+	if p.GoMod {
+		set["go.mod"] = string(tpl.GoModTemplate())
+	}
+	if p.RootTest {
+		set["cmd/root_test.go"] = string(tpl.RootTestTemplate())
+	}
+	if p.GenDocs {
+		set["cmd/gen_docs.go"] = string(tpl.GenDocsTemplate())
+	}
+	if p.Completion {
+		set["cmd/completion.go"] = string(tpl.CompletionTemplate())
+	}
+	return set
+}
+
+// loadTemplateDir returns the templates of the subdirectory kind, "init" or
+// "add", of the template directory dir, or nil if it has no such
+// subdirectory.  The files without the template suffix are escaped so that
+// they are copied as they are.
+func loadTemplateDir(dir, kind string) (templateSet, error) {
+	root := filepath.Join(dir, kind)
+	if fi, err := os.Stat(root); err != nil || !fi.IsDir() {
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	set := templateSet{}
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if strings.HasSuffix(rel, templateSuffix) {
+			set[strings.TrimSuffix(rel, templateSuffix)] = string(content)
+		} else {
+			set[rel] = escapeTemplate(string(content))
+		}
+		return nil
+	})
+	return set, err
+}
+
+// escapeTemplate returns a template whose output is s.
+func escapeTemplate(s string) string {
+	return strings.Replace(s, "{{", `{{ "{{" }}`, -1)
+}
+
+// render renders the templates of the set with data into the directory
+// dir.  It returns an error without writing any file if one of the files
+// already exists, unless force is set.
+func (set templateSet) render(dir string, data interface{}, force bool) error {
+	files := make(map[string]string, len(set))
+	var paths []string
+	for pathTemplate, contentTemplate := range set {
+		path, err := executeTemplate(pathTemplate, data)
+		if err != nil {
+			return fmt.Errorf("%s: %v", pathTemplate, err)
+		}
+		content, err := executeTemplate(contentTemplate, data)
+		if err != nil {
+			return fmt.Errorf("%s: %v", pathTemplate, err)
+		}
+		path = filepath.Join(dir, filepath.FromSlash(path))
+		files[path] = content
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		if !force && exists(path) {
+			return existsError(path)
+		}
+	}
+	for _, path := range paths {
+		if err := os.MkdirAll(filepath.Dir(path), 0751); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path, []byte(files[path]), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// templateVars returns the user-defined variables of the templates, from the
+// vars key of the configuration and from the --var flags, which take
+// precedence.
+func templateVars() map[string]string {
+	vars := cfg.getStringMap("vars")
+	for key, value := range userVars {
+		vars[key] = value
+	}
+	return vars
+}
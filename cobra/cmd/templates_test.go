@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTemplateDir writes files, by path relative to dir, into dir.
+func writeTemplateDir(t *testing.T, dir string, files map[string]string) {
+	for path, content := range files {
+		path = filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// checkFileContent checks that the file at path has the content expected.
+func checkFileContent(t *testing.T, path, expected string) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != expected {
+		t.Errorf("Expected %s to contain %q, got %q", path, expected, content)
+	}
+}
+
+func TestInitTemplateDir(t *testing.T) {
+	templateDir, err := ioutil.TempDir("", "cobra-templates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(templateDir)
+	writeTemplateDir(t, templateDir, map[string]string{
+		"init/main.go.tmpl":                       "package main // {{ .AppName }} by {{ .Vars.team }}\n",
+		"init/cmd/{{ .AppName }}_logging.go.tmpl": "package cmd // {{ .PkgName }}\n",
+		"init/Makefile":                           "build:\n\tgo build -ldflags '{{ not a template }}'\n",
+	})
+
+	project := getProject()
+	project.TemplateDir = templateDir
+	project.Vars = map[string]string{"team": "platform"}
+	defer os.RemoveAll(project.AbsolutePath)
+
+	if err := project.Create(); err != nil {
+		t.Fatal(err)
+	}
+	checkFileContent(t, filepath.Join(project.AbsolutePath, "main.go"), "package main // testproject by platform\n")
+	checkFileContent(t, filepath.Join(project.AbsolutePath, "cmd", "testproject_logging.go"), "package cmd // github.com/spf13/testproject\n")
+	checkFileContent(t, filepath.Join(project.AbsolutePath, "Makefile"), "build:\n\tgo build -ldflags '{{ not a template }}'\n")
+	if exists(filepath.Join(project.AbsolutePath, "cmd", "root.go")) {
+		t.Error("Expected the built-in templates to be replaced")
+	}
+	if !exists(filepath.Join(project.AbsolutePath, "LICENSE")) {
+		t.Error("Expected the LICENSE file to be created")
+	}
+}
+
+func TestAddTemplateDir(t *testing.T) {
+	templateDir, err := ioutil.TempDir("", "cobra-templates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(templateDir)
+
+	project := getProject()
+	defer os.RemoveAll(project.AbsolutePath)
+	if err := project.Create(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without add templates, the built-in templates are used
+	project.TemplateDir = templateDir
+	addCommands(t, project, false, "user")
+	if !exists(filepath.Join(project.AbsolutePath, "cmd", "user.go")) {
+		t.Fatal("Expected the built-in templates to be used")
+	}
+
+	writeTemplateDir(t, templateDir, map[string]string{
+		"add/cmd/{{ .CmdName }}.go.tmpl":      "package {{ .CmdPackage }} // {{ .CmdParent }}.AddCommand({{ .CmdVar }}) {{ .Vars.owner }}\n",
+		"add/cmd/{{ .CmdName }}_test.go.tmpl": "package {{ .CmdPackage }} // test {{ .CmdUse }}\n",
+	})
+	project.Vars = map[string]string{"owner": "platform"}
+	addCommands(t, project, false, "user/create")
+	checkFileContent(t, filepath.Join(project.AbsolutePath, "cmd", "userCreate.go"), "package cmd // userCmd.AddCommand(userCreateCmd) platform\n")
+	checkFileContent(t, filepath.Join(project.AbsolutePath, "cmd", "userCreate_test.go"), "package cmd // test create\n")
+
+	command, _ := newCommand("user/create", "rootCmd", false, false)
+	command.Project = project
+	if err := command.Create(); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Expected an error for the existing files, got %v", err)
+	}
+}
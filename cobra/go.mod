@@ -3,9 +3,8 @@ module github.com/spf13/cobra/cobra
 go 1.12
 
 require (
-	github.com/mitchellh/go-homedir v1.1.0
 	github.com/spf13/cobra v1.0.0
-	github.com/spf13/viper v1.7.0
+	gopkg.in/yaml.v2 v2.3.0
 )
 
 replace github.com/spf13/cobra => ../
@@ -2,7 +2,8 @@ package tpl
 
 func MainTemplate() []byte {
 	return []byte(`/*
-{{ .Copyright }}
+{{ if .Legal.SPDX }}SPDX-License-Identifier: {{ .Legal.SPDX }}
+{{ end }}{{ .Copyright }}
 {{ if .Legal.Header }}{{ .Legal.Header }}{{ end }}
 */
 package main
@@ -17,7 +18,8 @@ func main() {
 
 func RootTemplate() []byte {
 	return []byte(`/*
-{{ .Copyright }}
+{{ if .Legal.SPDX }}SPDX-License-Identifier: {{ .Legal.SPDX }}
+{{ end }}{{ .Copyright }}
 {{ if .Legal.Header }}{{ .Legal.Header }}{{ end }}
 */
 package cmd
@@ -27,7 +29,6 @@ import (
 	"github.com/spf13/cobra"
 	"os"
 {{ if .Viper }}
-	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/viper"
 {{ end -}}
 )
@@ -85,7 +86,7 @@ func initConfig() {
 		viper.SetConfigFile(cfgFile)
 	} else {
 		// Find home directory.
-		home, err := homedir.Dir()
+		home, err := os.UserHomeDir()
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
@@ -109,44 +110,233 @@ func initConfig() {
 
 func AddCommandTemplate() []byte {
 	return []byte(`/*
-{{ .Project.Copyright }}
+{{ if .Legal.SPDX }}SPDX-License-Identifier: {{ .Legal.SPDX }}
+{{ end }}{{ .Project.Copyright }}
 {{ if .Legal.Header }}{{ .Legal.Header }}{{ end }}
 */
-package cmd
+package {{ .CmdPackage }}
 
 import (
 	"fmt"
+{{- if .ImportsTime }}
+	"time"
+{{- end }}
 
 	"github.com/spf13/cobra"
 )
 
-// {{ .CmdName }}Cmd represents the {{ .CmdName }} command
-var {{ .CmdName }}Cmd = &cobra.Command{
-	Use:   "{{ .CmdName }}",
-	Short: "A brief description of your command",
-	Long: ` + "`" + `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
-
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.` + "`" + `,
+// {{ .CmdVar }} represents the {{ .CmdUse }} command
+var {{ .CmdVar }} = &cobra.Command{
+{{- range .CmdFields }}
+	{{ . }}
+{{- end }}
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("{{ .CmdName }} called")
+		fmt.Println("{{ .CmdUse }} called")
 	},
 }
 
 func init() {
-	{{ .CmdParent }}.AddCommand({{ .CmdName }}Cmd)
-
+{{- if not .PackageLayout }}
+	{{ .CmdParent }}.AddCommand({{ .CmdVar }})
+{{ end }}
+{{- if .Flags }}
+{{- range .CmdFlags }}
+	{{ . }}
+{{- end }}
+{{- else }}
 	// Here you will define your flags and configuration settings.
 
 	// Cobra supports Persistent Flags which will work for this command
 	// and all subcommands, e.g.:
-	// {{ .CmdName }}Cmd.PersistentFlags().String("foo", "", "A help for foo")
+	// {{ .CmdVar }}.PersistentFlags().String("foo", "", "A help for foo")
 
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
-	// {{ .CmdName }}Cmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	// {{ .CmdVar }}.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+{{- end }}
+}
+`)
+}
+
+func AddCommandRegistrationTemplate() []byte {
+	return []byte(`/*
+{{ if .Legal.SPDX }}SPDX-License-Identifier: {{ .Legal.SPDX }}
+{{ end }}{{ .Project.Copyright }}
+{{ if .Legal.Header }}{{ .Legal.Header }}{{ end }}
+*/
+package {{ .ParentPackage }}
+
+import (
+	"{{ .CmdImportPath }}"
+)
+
+func init() {
+	{{ .CmdParent }}.AddCommand({{ .CmdPackage }}.{{ .CmdVar }})
+}
+`)
+}
+
+func GoModTemplate() []byte {
+	return []byte(`module {{ .PkgName }}
+
+go 1.12
+
+require (
+	github.com/spf13/cobra v1.0.0
+{{- if .Viper }}
+	github.com/spf13/viper v1.7.0
+{{- end }}
+)
+`)
+}
+
+func RootTestTemplate() []byte {
+	return []byte(`/*
+{{ if .Legal.SPDX }}SPDX-License-Identifier: {{ .Legal.SPDX }}
+{{ end }}{{ .Copyright }}
+{{ if .Legal.Header }}{{ .Legal.Header }}{{ end }}
+*/
+package cmd
+
+import (
+	"bytes"
+	"testing"
+)
+
+// executeRootCmd executes the root command with args and returns its output.
+func executeRootCmd(args ...string) (string, error) {
+	buf := new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	rootCmd.SetErr(buf)
+	rootCmd.SetArgs(args)
+	defer rootCmd.SetArgs(nil)
+
+	err := rootCmd.Execute()
+	return buf.String(), err
+}
+
+func TestRootCmd(t *testing.T) {
+	output, err := executeRootCmd("--help")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output == "" {
+		t.Error("Expected the help of {{ .AppName }}")
+	}
+}
+`)
+}
+
+func GenDocsTemplate() []byte {
+	return []byte(`/*
+{{ if .Legal.SPDX }}SPDX-License-Identifier: {{ .Legal.SPDX }}
+{{ end }}{{ .Copyright }}
+{{ if .Legal.Header }}{{ .Legal.Header }}{{ end }}
+*/
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+// genDocsCmd generates the documentation of {{ .AppName }}
+var genDocsCmd = &cobra.Command{
+	Use:    "gen-docs [directory]",
+	Short:  "Generate the Markdown and man pages documentation",
+	Hidden: true,
+	Args:   cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := "docs"
+		if len(args) > 0 {
+			dir = args[0]
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		if err := doc.GenMarkdownTree(rootCmd, dir); err != nil {
+			return err
+		}
+		return doc.GenManTree(rootCmd, &doc.GenManHeader{Section: "1"}, dir)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(genDocsCmd)
+}
+`)
+}
+
+func CompletionTemplate() []byte {
+	return []byte(`/*
+{{ if .Legal.SPDX }}SPDX-License-Identifier: {{ .Legal.SPDX }}
+{{ end }}{{ .Copyright }}
+{{ if .Legal.Header }}{{ .Legal.Header }}{{ end }}
+*/
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// completionCmd generates the completion scripts of {{ .AppName }}
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate completion script",
+	Long: ` + "`" + `To load completions:
+
+Bash:
+
+$ source <({{ .AppName }} completion bash)
+
+# To load completions for each session, execute once:
+Linux:
+  $ {{ .AppName }} completion bash > /etc/bash_completion.d/{{ .AppName }}
+MacOS:
+  $ {{ .AppName }} completion bash > /usr/local/etc/bash_completion.d/{{ .AppName }}
+
+Zsh:
+
+# If shell completion is not already enabled in your environment you will need
+# to enable it.  You can execute the following once:
+
+$ echo "autoload -U compinit; compinit" >> ~/.zshrc
+
+# To load completions for each session, execute once:
+$ {{ .AppName }} completion zsh > "${fpath[1]}/_{{ .AppName }}"
+
+# You will need to start a new shell for this setup to take effect.
+
+Fish:
+
+$ {{ .AppName }} completion fish | source
+
+# To load completions for each session, execute once:
+$ {{ .AppName }} completion fish > ~/.config/fish/completions/{{ .AppName }}.fish
+` + "`" + `,
+	DisableFlagsInUseLine: true,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.ExactValidArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch args[0] {
+		case "bash":
+			return cmd.Root().GenBashCompletion(os.Stdout)
+		case "zsh":
+			return cmd.Root().GenZshCompletion(os.Stdout)
+		case "fish":
+			return cmd.Root().GenFishCompletion(os.Stdout, true)
+		default:
+			return cmd.Root().GenPowerShellCompletion(os.Stdout)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
 }
 `)
 }
@@ -20,3 +20,32 @@ func TestAddTemplateFunctions(t *testing.T) {
 		t.Errorf("Expected UsageString: %v\nGot: %v", expected, got)
 	}
 }
+
+func TestCommandTemplateFunctions(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.AddTemplateFuncs(template.FuncMap{
+		"greeting": func() string { return "Hello" },
+		"name":     func() string { return "root" },
+	})
+	childCmd := &Command{Use: "child", Run: emptyRun}
+	childCmd.AddTemplateFunc("name", func() string { return "child" })
+	rootCmd.AddCommand(childCmd)
+	otherCmd := &Command{Use: "other", Run: emptyRun}
+
+	rootCmd.SetUsageTemplate(`{{greeting}} {{name}} {{trim "  built-in  "}}`)
+	if got := rootCmd.UsageString(); got != "Hello root built-in" {
+		t.Errorf("Expected UsageString: %v\nGot: %v", "Hello root built-in", got)
+	}
+	// Inherited from the parent, and overridden by the command
+	if got := childCmd.UsageString(); got != "Hello child built-in" {
+		t.Errorf("Expected UsageString: %v\nGot: %v", "Hello child built-in", got)
+	}
+
+	// Not available to other command trees
+	if _, ok := otherCmd.TemplateFuncs()["greeting"]; ok {
+		t.Error("Expected the template functions of root to be unavailable to other commands")
+	}
+	if _, ok := templateFuncs["greeting"]; ok {
+		t.Error("Expected the package-level template functions to be unchanged")
+	}
+}
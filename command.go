@@ -18,12 +18,14 @@ package cobra
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"text/template"
 
 	flag "github.com/spf13/pflag"
 )
@@ -84,6 +86,10 @@ type Command struct {
 	// Deprecated defines, if this command is deprecated and should print this string when used.
 	Deprecated string
 
+	// Deprecation describes the deprecation of this command, with a replacement and a removal
+	// version.  Unlike with Deprecated, the command is still listed in the help of its parent.
+	Deprecation *Deprecation
+
 	// Hidden defines, if this command is hidden and should NOT show up in the list of available commands.
 	Hidden bool
 
@@ -150,10 +156,31 @@ type Command struct {
 	// SuggestionsMinimumDistance defines minimum levenshtein distance to display suggestions.
 	// Must be > 0.
 	SuggestionsMinimumDistance int
+	// SuggestAliases includes the aliases of the subcommands in the suggestions,
+	// including the aliases of hidden subcommands.
+	SuggestAliases bool
+	// SuggestNestedCommands includes all the descendants of the command in the
+	// suggestions, suggested with their path from the command, e.g. "config set".
+	SuggestNestedCommands bool
+	// SuggestValidArgs makes OnlyValidArgs suggest the closest ValidArgs for an
+	// invalid argument.
+	SuggestValidArgs bool
+	// SuggestionsDistanceFunc replaces the case-insensitive Levenshtein distance
+	// used to select and rank the suggestions.
+	SuggestionsDistanceFunc func(typedName, candidate string) int
 
 	// TraverseChildren parses flags on all parents before executing child command.
 	TraverseChildren bool
 
+	// PrefixMatching allows the subcommands of this command and of its
+	// descendants to be given by a prefix of their name or aliases, like
+	// EnablePrefixMatching does for all the commands.
+	PrefixMatching bool
+	// FlagAbbreviations allows the long flags of this command and of its
+	// descendants to be given by a unique prefix of their name, e.g. --names
+	// for --namespace.
+	FlagAbbreviations bool
+
 	// FParseErrWhitelist flag parse errors to be ignored
 	FParseErrWhitelist FParseErrWhitelist
 
@@ -209,6 +236,40 @@ type Command struct {
 	helpCommand *Command
 	// versionTemplate is the version template defined by user.
 	versionTemplate string
+	// templateFuncs are the template functions defined by user.
+	templateFuncs template.FuncMap
+	// helpWidth is the width of the help output defined by user.
+	helpWidth int
+	// helpStyle is the styling of the help output defined by user.
+	helpStyle *HelpStyle
+	// helpPager is the pager of the help output defined by user.
+	helpPager HelpPager
+	// deprecationHook is the hook called for deprecated commands and flags defined by user.
+	deprecationHook DeprecationHook
+	// messageCatalog is the catalog of translated messages defined by user.
+	messageCatalog MessageCatalog
+	// locale is the locale of the messages defined by user.
+	locale string
+	// aliasSource is the source of the aliases defined by the users.
+	aliasSource AliasSource
+	// pluginsEnabled is true if the plugins are enabled by user.
+	pluginsEnabled bool
+	// pluginDirs are the directories of the plugins defined by user.
+	pluginDirs []string
+	// pluginPath is the path of the executable run by a plugin command.
+	pluginPath string
+	// pluginFiles are the paths of the executables of the plugin directories
+	// by name, scanned once per execution.
+	pluginFiles map[string]string
+	// commandFactories are the factories of subcommands registered by user
+	// and not resolved yet.
+	commandFactories []commandFactory
+	// packageFactoriesAdded is true once the factories registered for the
+	// tree at the package level are added to its root.
+	packageFactoriesAdded bool
+	// commandFactoryErrors are the registered commands of the tree which
+	// can't be added, recorded on its root.
+	commandFactoryErrors commandFactoryErrors
 
 	// inReader is a reader defined by the user that replaces stdin
 	inReader io.Reader
@@ -287,11 +348,67 @@ func (c *Command) SetHelpTemplate(s string) {
 	c.helpTemplate = s
 }
 
+// SetHelpWidth sets the width to which the help output is wrapped, instead of
+// detecting it.  A negative width disables wrapping.  It is inherited by the
+// sub-commands.
+func (c *Command) SetHelpWidth(width int) {
+	c.helpWidth = width
+}
+
+// SetHelpStyle sets the ANSI styling of the help output, for example
+// DefaultHelpStyle.  It is inherited by the sub-commands, and only applied
+// when OutOrStdout() is a terminal and the NO_COLOR environment variable is
+// not set.
+func (c *Command) SetHelpStyle(style *HelpStyle) {
+	c.helpStyle = style
+}
+
 // SetVersionTemplate sets version template to be used. Application can use it to set custom template.
 func (c *Command) SetVersionTemplate(s string) {
 	c.versionTemplate = s
 }
 
+// AddTemplateFunc adds a template function that's available to the Usage,
+// Help and Version templates of this command and of its descendants.
+// It takes precedence over a function of the same name added by a parent
+// command or with the package-level AddTemplateFunc.
+func (c *Command) AddTemplateFunc(name string, tmplFunc interface{}) {
+	if c.templateFuncs == nil {
+		c.templateFuncs = template.FuncMap{}
+	}
+	c.templateFuncs[name] = tmplFunc
+}
+
+// AddTemplateFuncs adds multiple template functions that are available to
+// the Usage, Help and Version templates of this command and of its descendants.
+func (c *Command) AddTemplateFuncs(tmplFuncs template.FuncMap) {
+	for k, v := range tmplFuncs {
+		c.AddTemplateFunc(k, v)
+	}
+}
+
+// TemplateFuncs returns the template functions available to the templates of
+// this command: the built-in ones, the ones added with the package-level
+// AddTemplateFunc and the ones added to this command and its parents.
+// It can be used to render custom templates, e.g. for documentation.
+func (c *Command) TemplateFuncs() template.FuncMap {
+	funcs := template.FuncMap{}
+	for k, v := range templateFuncs {
+		funcs[k] = v
+	}
+	var addFuncs func(*Command)
+	addFuncs = func(cmd *Command) {
+		if cmd.HasParent() {
+			addFuncs(cmd.Parent())
+		}
+		for k, v := range cmd.templateFuncs {
+			funcs[k] = v
+		}
+	}
+	addFuncs(c)
+	return funcs
+}
+
 // SetGlobalNormalizationFunc sets a normalization function to all flag sets and also to child commands.
 // The user should not have a cyclic dependency on commands.
 func (c *Command) SetGlobalNormalizationFunc(n func(f *flag.FlagSet, name string) flag.NormalizedName) {
@@ -391,6 +508,17 @@ func (c *Command) HelpFunc() func(*Command, []string) {
 	}
 	return func(c *Command, a []string) {
 		c.mergePersistentFlags()
+		if pager := c.helpPagerInUse(); pager != nil {
+			help := new(bytes.Buffer)
+			err := tmpl(help, c.HelpTemplate(), c)
+			if err == nil {
+				err = pager(c, help.String())
+			}
+			if err != nil {
+				c.Println(err)
+			}
+			return
+		}
 		// The help should be sent to stdout
 		// See https://github.com/spf13/cobra/issues/1002
 		err := tmpl(c.OutOrStdout(), c.HelpTemplate(), c)
@@ -482,29 +610,35 @@ func (c *Command) UsageTemplate() string {
 	if c.HasParent() {
 		return c.parent.UsageTemplate()
 	}
-	return `Usage:{{if .Runnable}}
+	return `{{.HelpHeading "Usage:"}}{{if .Runnable}}
   {{.UseLine}}{{end}}{{if .HasAvailableSubCommands}}
   {{.CommandPath}} [command]{{end}}{{if gt (len .Aliases) 0}}
 
-Aliases:
+{{.HelpHeading "Aliases:"}}
   {{.NameAndAliases}}{{end}}{{if .HasExample}}
 
-Examples:
+{{.HelpHeading "Examples:"}}
 {{.Example}}{{end}}{{if .HasAvailableSubCommands}}
 
-Available Commands:{{range .Commands}}{{if (or .IsAvailableCommand (eq .Name "help"))}}
-  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{end}}{{if .HasAvailableLocalFlags}}
+{{.HelpHeading "Available Commands:"}}{{range .Commands}}{{if (or .IsAvailableCommand (eq .Name "help"))}}
+{{$.HelpEntry .Name .NamePadding .Short}}{{end}}{{end}}{{end}}{{if .HasUserAliases}}
 
-Flags:
-{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasAvailableInheritedFlags}}
+{{.HelpHeading "User Aliases:"}}{{range .UserAliases}}
+{{$.HelpEntry .Name .NamePadding .Expansion}}{{end}}{{end}}{{if .HasPlugins}}
 
-Global Flags:
-{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasHelpSubCommands}}
+{{.HelpHeading "Plugins:"}}{{range .Plugins}}
+{{$.HelpEntry .Name .NamePadding .Path}}{{end}}{{end}}{{if .HasAvailableLocalFlags}}
 
-Additional help topics:{{range .Commands}}{{if .IsAdditionalHelpTopicCommand}}
-  {{rpad .CommandPath .CommandPathPadding}} {{.Short}}{{end}}{{end}}{{end}}{{if .HasAvailableSubCommands}}
+{{.HelpHeading "Flags:"}}
+{{.HelpFlagUsages .LocalFlags | trimTrailingWhitespaces}}{{end}}{{if .HasAvailableInheritedFlags}}
 
-Use "{{.CommandPath}} [command] --help" for more information about a command.{{end}}
+{{.HelpHeading "Global Flags:"}}
+{{.HelpFlagUsages .InheritedFlags | trimTrailingWhitespaces}}{{end}}{{if .HasHelpSubCommands}}
+
+{{.HelpHeading "Additional help topics:"}}{{range .Commands}}{{if .IsAdditionalHelpTopicCommand}}
+{{$.HelpEntry .CommandPath .CommandPathPadding .Short}}{{end}}{{end}}{{end}}{{if .HasAvailableSubCommands}}
+
+{{.Translate "Use \"%s [command] --help\" for more information about a command." .CommandPath}}{{end}}
 `
 }
 
@@ -517,7 +651,9 @@ func (c *Command) HelpTemplate() string {
 	if c.HasParent() {
 		return c.parent.HelpTemplate()
 	}
-	return `{{with (or .Long .Short)}}{{. | trimTrailingWhitespaces}}
+	return `{{with .DeprecationNotice}}{{$.HelpText .}}
+
+{{end}}{{with (or .Long .Short)}}{{$.HelpText . | trimTrailingWhitespaces}}
 
 {{end}}{{if or .Runnable .HasSubCommands}}{{.UsageString}}{{end}}`
 }
@@ -531,7 +667,7 @@ func (c *Command) VersionTemplate() string {
 	if c.HasParent() {
 		return c.parent.VersionTemplate()
 	}
-	return `{{with .Name}}{{printf "%s " .}}{{end}}{{printf "version %s" .Version}}
+	return `{{with .Name}}{{printf "%s " .}}{{end}}{{.Translate "version %s" .VersionInfo.Version}}
 `
 }
 
@@ -559,38 +695,43 @@ func stripFlags(args []string, c *Command) []string {
 	if len(args) == 0 {
 		return args
 	}
-	c.mergePersistentFlags()
 
 	commands := []string{}
+	for _, i := range nonFlagArgIndexes(args, c) {
+		commands = append(commands, args[i])
+	}
+	return commands
+}
+
+// nonFlagArgIndexes returns the indexes in args of the arguments which are
+// neither flags nor the values of flags, up to the "--" terminating the
+// flags.
+func nonFlagArgIndexes(args []string, c *Command) []int {
+	c.mergePersistentFlags()
+
+	indexes := []int{}
 	flags := c.Flags()
 
-Loop:
-	for len(args) > 0 {
-		s := args[0]
-		args = args[1:]
+	for i := 0; i < len(args); i++ {
+		s := args[i]
 		switch {
 		case s == "--":
 			// "--" terminates the flags
-			break Loop
-		case strings.HasPrefix(s, "--") && !strings.Contains(s, "=") && !hasNoOptDefVal(s[2:], flags):
+			return indexes
+		case strings.HasPrefix(s, "--") && !strings.Contains(s, "=") && !hasNoOptDefVal(c.expandFlagName(s[2:]), flags):
 			// If '--flag arg' then
-			// delete arg from args.
+			// skip arg.
 			fallthrough // (do the same as below)
 		case strings.HasPrefix(s, "-") && !strings.Contains(s, "=") && len(s) == 2 && !shortHasNoOptDefVal(s[1:], flags):
 			// If '-f arg' then
-			// delete 'arg' from args or break the loop if len(args) <= 1.
-			if len(args) <= 1 {
-				break Loop
-			} else {
-				args = args[1:]
-				continue
-			}
+			// skip 'arg'.
+			i++
 		case s != "" && !strings.HasPrefix(s, "-"):
-			commands = append(commands, s)
+			indexes = append(indexes, i)
 		}
 	}
 
-	return commands
+	return indexes
 }
 
 // argsMinusFirstX removes only the first x from args.  Otherwise, commands that look like
@@ -615,23 +756,38 @@ func isFlagArg(arg string) bool {
 // Find the target command given the args and command tree
 // Meant to be run on the highest node. Only searches down.
 func (c *Command) Find(args []string) (*Command, []string, error) {
-	var innerfind func(*Command, []string) (*Command, []string)
+	expandedArgs, err := c.expandUserAliases(args)
+	if err != nil {
+		return c, args, err
+	}
+	args = expandedArgs
 
-	innerfind = func(c *Command, innerArgs []string) (*Command, []string) {
+	var innerfind func(*Command, []string) (*Command, []string, error)
+
+	innerfind = func(c *Command, innerArgs []string) (*Command, []string, error) {
 		argsWOflags := stripFlags(innerArgs, c)
 		if len(argsWOflags) == 0 {
-			return c, innerArgs
+			return c, innerArgs, nil
 		}
 		nextSubCmd := argsWOflags[0]
 
-		cmd := c.findNext(nextSubCmd)
+		cmd, err := c.findNext(nextSubCmd)
+		if err != nil {
+			return c, innerArgs, err
+		}
 		if cmd != nil {
 			return innerfind(cmd, argsMinusFirstX(innerArgs, nextSubCmd))
 		}
-		return c, innerArgs
+		if plugin, pluginArgs := c.findPlugin(innerArgs, argsWOflags); plugin != nil {
+			return plugin, pluginArgs, nil
+		}
+		return c, innerArgs, nil
 	}
 
-	commandFound, a := innerfind(c, args)
+	commandFound, a, err := innerfind(c, args)
+	if err != nil {
+		return commandFound, a, err
+	}
 	if commandFound.Args == nil {
 		return commandFound, a, legacyArgs(commandFound, stripFlags(a, commandFound))
 	}
@@ -645,9 +801,14 @@ func (c *Command) findSuggestions(arg string) string {
 	if c.SuggestionsMinimumDistance <= 0 {
 		c.SuggestionsMinimumDistance = 2
 	}
+	return c.formatSuggestions(c.SuggestionsFor(arg))
+}
+
+// formatSuggestions returns the suggestions as appended to error messages.
+func (c *Command) formatSuggestions(suggestions []string) string {
 	suggestionsString := ""
-	if suggestions := c.SuggestionsFor(arg); len(suggestions) > 0 {
-		suggestionsString += "\n\nDid you mean this?\n"
+	if len(suggestions) > 0 {
+		suggestionsString += "\n\n" + c.Translate("Did you mean this?") + "\n"
 		for _, s := range suggestions {
 			suggestionsString += fmt.Sprintf("\t%v\n", s)
 		}
@@ -655,28 +816,37 @@ func (c *Command) findSuggestions(arg string) string {
 	return suggestionsString
 }
 
-func (c *Command) findNext(next string) *Command {
+func (c *Command) findNext(next string) (*Command, error) {
 	matches := make([]*Command, 0)
 	for _, cmd := range c.commands {
 		if cmd.Name() == next || cmd.HasAlias(next) {
 			cmd.commandCalledAs.name = next
-			return cmd
+			return cmd, nil
 		}
-		if EnablePrefixMatching && cmd.hasNameOrAliasPrefix(next) {
+		if c.prefixMatching() && cmd.hasNameOrAliasPrefix(next) {
 			matches = append(matches, cmd)
 		}
 	}
 
 	if len(matches) == 1 {
-		return matches[0]
+		return matches[0], nil
+	}
+	if len(matches) > 1 {
+		return nil, c.ambiguousCommandError(next, matches)
 	}
 
-	return nil
+	return nil, nil
 }
 
 // Traverse the command tree to find the command, and parse args for
 // each parent.
 func (c *Command) Traverse(args []string) (*Command, []string, error) {
+	expandedArgs, err := c.expandUserAliases(args)
+	if err != nil {
+		return c, args, err
+	}
+	args = expandedArgs
+
 	flags := []string{}
 	inFlag := false
 
@@ -685,7 +855,7 @@ func (c *Command) Traverse(args []string) (*Command, []string, error) {
 		// A long flag with a space separated value
 		case strings.HasPrefix(arg, "--") && !strings.Contains(arg, "="):
 			// TODO: this isn't quite right, we should really check ahead for 'true' or 'false'
-			inFlag = !hasNoOptDefVal(arg[2:], c.Flags())
+			inFlag = !hasNoOptDefVal(c.expandFlagName(arg[2:]), c.Flags())
 			flags = append(flags, arg)
 			continue
 		// A short flag with a space separated value
@@ -704,9 +874,19 @@ func (c *Command) Traverse(args []string) (*Command, []string, error) {
 			continue
 		}
 
-		cmd := c.findNext(arg)
+		cmd, err := c.findNext(arg)
+		if err != nil {
+			return c, args, err
+		}
 		if cmd == nil {
-			return c, args, nil
+			plugin, pluginArgs := c.findPlugin(args[i:], args[i:])
+			if plugin == nil {
+				return c, args, nil
+			}
+			if err := c.ParseFlags(flags); err != nil {
+				return nil, args, err
+			}
+			return plugin, pluginArgs, nil
 		}
 
 		if err := c.ParseFlags(flags); err != nil {
@@ -717,27 +897,170 @@ func (c *Command) Traverse(args []string) (*Command, []string, error) {
 	return c, args, nil
 }
 
-// SuggestionsFor provides suggestions for the typedName.
+// SuggestionsFor provides suggestions for the typedName, from the closest
+// to the farthest.  The subcommands are suggested by name, and with
+// SuggestAliases and SuggestNestedCommands by alias and as nested commands.
 func (c *Command) SuggestionsFor(typedName string) []string {
-	suggestions := []string{}
-	for _, cmd := range c.commands {
-		if cmd.IsAvailableCommand() {
-			levenshteinDistance := ld(typedName, cmd.Name(), true)
-			suggestByLevenshtein := levenshteinDistance <= c.SuggestionsMinimumDistance
-			suggestByPrefix := strings.HasPrefix(strings.ToLower(cmd.Name()), strings.ToLower(typedName))
-			if suggestByLevenshtein || suggestByPrefix {
-				suggestions = append(suggestions, cmd.Name())
+	var candidates []suggestionCandidate
+	var visit func(cmd *Command, path string)
+	visit = func(cmd *Command, path string) {
+		for _, sub := range cmd.commands {
+			subPath := strings.TrimPrefix(path+" "+sub.Name(), " ")
+			if sub.IsAvailableCommand() {
+				candidates = append(candidates, suggestionCandidate{sub.Name(), subPath})
+				for _, explicitSuggestion := range sub.SuggestFor {
+					if strings.EqualFold(typedName, explicitSuggestion) {
+						candidates = append(candidates, suggestionCandidate{typedName, subPath})
+					}
+				}
 			}
-			for _, explicitSuggestion := range cmd.SuggestFor {
-				if strings.EqualFold(typedName, explicitSuggestion) {
-					suggestions = append(suggestions, cmd.Name())
+			if c.suggestAliases() && len(sub.Deprecated) == 0 {
+				for _, alias := range sub.Aliases {
+					candidates = append(candidates, suggestionCandidate{alias, strings.TrimPrefix(path+" "+alias, " ")})
 				}
 			}
+			if c.suggestNestedCommands() && sub.IsAvailableCommand() {
+				visit(sub, subPath)
+			}
+		}
+	}
+	visit(c, "")
+	return c.rankSuggestions(typedName, candidates)
+}
+
+// validArgsSuggestions returns the suggestions among validArgs for the
+// invalid argument arg.
+func (c *Command) validArgsSuggestions(arg string, validArgs []string) string {
+	if c.suggestionsDisabled() {
+		return ""
+	}
+	var candidates []suggestionCandidate
+	for _, v := range validArgs {
+		candidates = append(candidates, suggestionCandidate{v, v})
+	}
+	return c.formatSuggestions(c.rankSuggestions(arg, candidates))
+}
+
+// suggestionCandidate is a name compared to the typed name, and the
+// suggestion made if it is close enough.
+type suggestionCandidate struct {
+	name       string
+	suggestion string
+}
+
+// rankSuggestions returns the suggestions of the candidates whose name is
+// within the minimum distance of typedName or starts with it, sorted by
+// distance.  A suggestion is only returned once, at its best rank.
+func (c *Command) rankSuggestions(typedName string, candidates []suggestionCandidate) []string {
+	minDistance := c.suggestionsMinimumDistance()
+	distances := map[string]int{}
+	suggestions := []string{}
+	for _, candidate := range candidates {
+		distance := c.suggestionsDistance(typedName, candidate.name)
+		suggestByLevenshtein := distance <= minDistance
+		suggestByPrefix := strings.HasPrefix(strings.ToLower(candidate.name), strings.ToLower(typedName))
+		if !suggestByLevenshtein && !suggestByPrefix {
+			continue
+		}
+		if d, ok := distances[candidate.suggestion]; ok {
+			if distance < d {
+				distances[candidate.suggestion] = distance
+			}
+			continue
 		}
+		distances[candidate.suggestion] = distance
+		suggestions = append(suggestions, candidate.suggestion)
 	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return distances[suggestions[i]] < distances[suggestions[j]]
+	})
 	return suggestions
 }
 
+// FlagSuggestionsFor provides suggestions among the flags of the command for
+// the unknown flag typedName, given with its leading dashes.  The suggestions
+// are the flag names with their dashes, such as "--namespace" or "-n".
+func (c *Command) FlagSuggestionsFor(typedName string) []string {
+	name := strings.TrimLeft(typedName, "-")
+	var shorthands []string
+	var candidates []suggestionCandidate
+	c.Flags().VisitAll(func(f *flag.Flag) {
+		if f.Hidden || len(f.Deprecated) > 0 || name == "" {
+			return
+		}
+		if len(name) == 1 {
+			if f.Shorthand != "" && len(f.ShorthandDeprecated) == 0 && f.Shorthand != name && strings.EqualFold(f.Shorthand, name) {
+				shorthands = append(shorthands, "-"+f.Shorthand)
+			}
+			return
+		}
+		// A distance as long as the name means nothing in common
+		if c.suggestionsDistance(name, f.Name) < len(name) || strings.HasPrefix(strings.ToLower(f.Name), strings.ToLower(name)) {
+			candidates = append(candidates, suggestionCandidate{f.Name, "--" + f.Name})
+		}
+	})
+	if len(name) == 1 {
+		return shorthands
+	}
+	return c.rankSuggestions(name, candidates)
+}
+
+// suggestionsMinimumDistance returns the SuggestionsMinimumDistance of
+// the command or of its closest parent setting it, or 2 by default.
+func (c *Command) suggestionsMinimumDistance() int {
+	for p := c; p != nil; p = p.parent {
+		if p.SuggestionsMinimumDistance > 0 {
+			return p.SuggestionsMinimumDistance
+		}
+	}
+	return 2
+}
+
+// suggestionsDistance returns the distance between typedName and candidate,
+// using the SuggestionsDistanceFunc of the command or of its closest parent
+// setting it, or else the case-insensitive Levenshtein distance.
+func (c *Command) suggestionsDistance(typedName, candidate string) int {
+	for p := c; p != nil; p = p.parent {
+		if p.SuggestionsDistanceFunc != nil {
+			return p.SuggestionsDistanceFunc(typedName, candidate)
+		}
+	}
+	return ld(typedName, candidate, true)
+}
+
+// suggestAliases returns true if SuggestAliases is set on the command or
+// on one of its parents.
+func (c *Command) suggestAliases() bool {
+	for p := c; p != nil; p = p.parent {
+		if p.SuggestAliases {
+			return true
+		}
+	}
+	return false
+}
+
+// suggestNestedCommands returns true if SuggestNestedCommands is set on the
+// command or on one of its parents.
+func (c *Command) suggestNestedCommands() bool {
+	for p := c; p != nil; p = p.parent {
+		if p.SuggestNestedCommands {
+			return true
+		}
+	}
+	return false
+}
+
+// suggestValidArgs returns true if SuggestValidArgs is set on the command or
+// on one of its parents.
+func (c *Command) suggestValidArgs() bool {
+	for p := c; p != nil; p = p.parent {
+		if p.SuggestValidArgs {
+			return true
+		}
+	}
+	return false
+}
+
 // VisitParents visits all parents of the command and invokes fn on each parent.
 func (c *Command) VisitParents(fn func(*Command)) {
 	if c.HasParent() {
@@ -765,8 +1088,8 @@ func (c *Command) execute(a []string) (err error) {
 		return fmt.Errorf("Called Execute() on a nil Command")
 	}
 
-	if len(c.Deprecated) > 0 {
-		c.Printf("Command %q is deprecated, %s\n", c.Name(), c.Deprecated)
+	if err := c.checkCommandDeprecation(); err != nil {
+		return err
 	}
 
 	// initialize help and version flag at the last point possible to allow for user
@@ -778,6 +1101,9 @@ func (c *Command) execute(a []string) (err error) {
 	if err != nil {
 		return c.FlagErrorFunc()(c, err)
 	}
+	if err := c.checkFlagDeprecations(); err != nil {
+		return err
+	}
 
 	// If help is called, regardless of other flags, return we want help.
 	// Also say we need help if the command isn't runnable.
@@ -912,6 +1238,13 @@ func (c *Command) ExecuteC() (cmd *Command, err error) {
 		preExecHookFn(c)
 	}
 
+	// rescan the plugin directories
+	c.pluginFiles = nil
+
+	// add the registered commands before looking up the command to execute,
+	// leaving out the ones which can't be added
+	_ = c.ResolveCommands()
+
 	// initialize help as the last point possible to allow for user
 	// overriding
 	c.InitDefaultHelpCmd()
@@ -938,8 +1271,8 @@ func (c *Command) ExecuteC() (cmd *Command, err error) {
 			c = cmd
 		}
 		if !c.SilenceErrors {
-			c.Println("Error:", err.Error())
-			c.Printf("Run '%v --help' for usage.\n", c.CommandPath())
+			c.Println(c.Translate("Error:"), err.Error())
+			c.Println(c.Translate("Run '%v --help' for usage.", c.CommandPath()))
 		}
 		return c, err
 	}
@@ -967,7 +1300,7 @@ func (c *Command) ExecuteC() (cmd *Command, err error) {
 		// If root command has SilentErrors flagged,
 		// all subcommands should respect it
 		if !cmd.SilenceErrors && !c.SilenceErrors {
-			c.Println("Error:", err.Error())
+			c.Println(c.Translate("Error:"), err.Error())
 		}
 
 		// If root command has SilentUsage flagged,
@@ -1004,7 +1337,7 @@ func (c *Command) validateRequiredFlags() error {
 	})
 
 	if len(missingFlagNames) > 0 {
-		return fmt.Errorf(`required flag(s) "%s" not set`, strings.Join(missingFlagNames, `", "`))
+		return errors.New(c.Translate(`required flag(s) "%s" not set`, strings.Join(missingFlagNames, `", "`)))
 	}
 	return nil
 }
@@ -1015,13 +1348,11 @@ func (c *Command) validateRequiredFlags() error {
 func (c *Command) InitDefaultHelpFlag() {
 	c.mergePersistentFlags()
 	if c.Flags().Lookup("help") == nil {
-		usage := "help for "
-		if c.Name() == "" {
-			usage += "this command"
-		} else {
-			usage += c.Name()
+		name := c.Name()
+		if name == "" {
+			name = c.Translate("this command")
 		}
-		c.Flags().BoolP("help", "h", false, usage)
+		c.Flags().BoolP("help", "h", false, c.Translate("help for %s", name))
 	}
 }
 
@@ -1036,12 +1367,11 @@ func (c *Command) InitDefaultVersionFlag() {
 
 	c.mergePersistentFlags()
 	if c.Flags().Lookup("version") == nil {
-		usage := "version for "
-		if c.Name() == "" {
-			usage += "this command"
-		} else {
-			usage += c.Name()
+		name := c.Name()
+		if name == "" {
+			name = c.Translate("this command")
 		}
+		usage := c.Translate("version for %s", name)
 		if c.Flags().ShorthandLookup("v") == nil {
 			c.Flags().BoolP("version", "v", false, usage)
 		} else {
@@ -1061,9 +1391,9 @@ func (c *Command) InitDefaultHelpCmd() {
 	if c.helpCommand == nil {
 		c.helpCommand = &Command{
 			Use:   "help [command]",
-			Short: "Help about any command",
-			Long: `Help provides help for any command in the application.
-Simply type ` + c.Name() + ` help [path to command] for full details.`,
+			Short: c.Translate("Help about any command"),
+			Long: c.Translate(`Help provides help for any command in the application.
+Simply type %s help [path to command] for full details.`, c.Name()),
 			ValidArgsFunction: func(c *Command, args []string, toComplete string) ([]string, ShellCompDirective) {
 				var completions []string
 				cmd, _, e := c.Root().Find(args)
@@ -1085,8 +1415,12 @@ Simply type ` + c.Name() + ` help [path to command] for full details.`,
 			},
 			Run: func(c *Command, args []string) {
 				cmd, _, e := c.Root().Find(args)
+				if term, _ := c.Flags().GetString("search"); term != "" && cmd != nil && e == nil {
+					cmd.printHelpSearch(term)
+					return
+				}
 				if cmd == nil || e != nil {
-					c.Printf("Unknown help topic %#q\n", args)
+					c.Println(c.Translate("Unknown help topic %#q", args))
 					c.Root().Usage()
 				} else {
 					cmd.InitDefaultHelpFlag() // make possible 'help' flag to be shown
@@ -1094,6 +1428,7 @@ Simply type ` + c.Name() + ` help [path to command] for full details.`,
 				}
 			},
 		}
+		c.helpCommand.Flags().String("search", "", c.Translate("search the help of the commands for a term"))
 	}
 	c.RemoveCommand(c.helpCommand)
 	c.AddCommand(c.helpCommand)
@@ -1620,13 +1955,20 @@ func (c *Command) ParseFlags(args []string) error {
 	// do it here after merging all flags and just before parse
 	c.Flags().ParseErrorsWhitelist = flag.ParseErrorsWhitelist(c.FParseErrWhitelist)
 
-	err := c.Flags().Parse(args)
+	args, err := c.expandFlagAbbreviations(args)
+	if err != nil {
+		return err
+	}
+	err = c.Flags().Parse(args)
 	// Print warnings if they occurred (e.g. deprecated flag messages).
 	if c.flagErrorBuf.Len()-beforeErrorBufLen > 0 && err == nil {
-		c.Print(c.flagErrorBuf.String())
+		c.PrintErr(c.flagErrorBuf.String())
+	}
+	if err != nil {
+		return c.unknownFlagError(err)
 	}
 
-	return err
+	return c.validateFlagValues()
 }
 
 // Parent returns a commands parent command.
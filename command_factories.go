@@ -0,0 +1,186 @@
+package cobra
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// CommandFactory returns a new command to add to a command tree.
+type CommandFactory func() *Command
+
+// commandFactory is a factory registered for the subcommands of the command
+// with the given path.
+type commandFactory struct {
+	parentPath []string
+	factory    CommandFactory
+}
+
+var (
+	commandFactoriesMu sync.Mutex
+	// commandFactories are the factories registered with the package-level
+	// RegisterCommandFactory, by path from the root.
+	commandFactories []commandFactory
+)
+
+// RegisterCommandFactory registers factory to add its command to the command
+// tree whose root command is named after the first word of parentPath, as a
+// subcommand of the command with the rest of the path, e.g. "app" or
+// "app user".  It lets a package contribute a subcommand to a program which
+// just imports it, typically calling RegisterCommandFactory from its init()
+// function.  The factory is called when the tree is resolved, as described by
+// Command.ResolveCommands.
+//
+// Example:
+//   func init() {
+//     cobra.RegisterCommandFactory("app user", func() *cobra.Command {
+//       return &cobra.Command{Use: "export", Short: "Export the users", RunE: export}
+//     })
+//   }
+func RegisterCommandFactory(parentPath string, factory CommandFactory) {
+	commandFactoriesMu.Lock()
+	defer commandFactoriesMu.Unlock()
+	commandFactories = append(commandFactories, commandFactory{strings.Fields(parentPath), factory})
+}
+
+// RegisterCommandFactory registers factory to add its command as a subcommand
+// of the descendant of c with the given path from c, e.g. "user" or
+// "user group", or of c itself if the path is empty.  The factory is called
+// when the tree is resolved, as described by ResolveCommands.
+func (c *Command) RegisterCommandFactory(parentPath string, factory CommandFactory) {
+	c.commandFactories = append(c.commandFactories, commandFactory{strings.Fields(parentPath), factory})
+}
+
+// ResolveCommands adds the commands of the registered factories to the tree of
+// c.  A command can't be added when its parent isn't in the tree, when its
+// factory returns nil, or when its name or one of its aliases is already
+// used by another subcommand of its parent, be it declared or registered.
+// The other commands are still added.  The commands which can't be added are
+// printed as warnings to the error output of the root command when they are
+// found, unless it silences its errors, and returned as an error, one per
+// line, by every call of ResolveCommands, so that a test of the tree catches
+// them.
+//
+// The commands are added from the root command down, a command being
+// resolved before its subcommands, so that factories can contribute to the
+// commands of other factories.  A factory registered with a path is handed
+// down to the command at the first word of its path, whose factories are
+// called in their order of registration, the ones handed down by its parents
+// first, and the ones of the package-level RegisterCommandFactory before all.
+// Each factory is called once.
+//
+// The tree is resolved by Execute(), by Lint, by the documentation
+// generators and by GenBashCompletion, which all run without the commands
+// which can't be added.
+func (c *Command) ResolveCommands() error {
+	root := c.Root()
+	for _, e := range root.resolveTree() {
+		if !root.SilenceErrors {
+			root.PrintErr(root.Translate("Warning:") + " " + e.err.Error() + "\n")
+		}
+	}
+	if len(root.commandFactoryErrors) == 0 {
+		return nil
+	}
+	return root.commandFactoryErrors
+}
+
+// commandFactoryError is a registered command which can't be added to the
+// tree, reported on its parent command.
+type commandFactoryError struct {
+	parent *Command
+	err    error
+}
+
+// commandFactoryErrors are the registered commands which can't be added to
+// a tree.
+type commandFactoryErrors []commandFactoryError
+
+func (e commandFactoryErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// resolveTree adds the commands of the factories registered on the root
+// command c, on its descendants and with the package-level
+// RegisterCommandFactory.  It records the commands which can't be added on
+// c, and returns the ones found by this call.
+func (c *Command) resolveTree() commandFactoryErrors {
+	if !c.packageFactoriesAdded {
+		c.packageFactoriesAdded = true
+		commandFactoriesMu.Lock()
+		var factories []commandFactory
+		for _, f := range commandFactories {
+			if len(f.parentPath) > 0 && f.parentPath[0] == c.Name() {
+				factories = append(factories, commandFactory{f.parentPath[1:], f.factory})
+			}
+		}
+		commandFactoriesMu.Unlock()
+		c.commandFactories = append(factories, c.commandFactories...)
+	}
+	var errs commandFactoryErrors
+	c.resolveCommands(func(parent *Command, err error) {
+		errs = append(errs, commandFactoryError{parent, err})
+	})
+	c.commandFactoryErrors = append(c.commandFactoryErrors, errs...)
+	return errs
+}
+
+// resolveCommands adds the commands of the factories registered on c, and
+// hands the ones registered for its descendants down to its subcommands
+// before resolving them.
+func (c *Command) resolveCommands(fail func(*Command, error)) {
+	factories := c.commandFactories
+	c.commandFactories = nil
+
+	// The subcommands of c first, as the other factories may need them
+factories:
+	for _, f := range factories {
+		if len(f.parentPath) > 0 {
+			continue
+		}
+		cmd := f.factory()
+		if cmd == nil {
+			fail(c, fmt.Errorf("a command factory registered for %q returned no command", c.CommandPath()))
+			continue
+		}
+		for _, name := range append([]string{cmd.Name()}, cmd.Aliases...) {
+			for _, sub := range c.commands {
+				if sub.Name() == name || sub.HasAlias(name) {
+					fail(c, fmt.Errorf("registered command %q conflicts with the command %q: %q is already used",
+						c.CommandPath()+" "+cmd.Name(), sub.CommandPath(), name))
+					continue factories
+				}
+			}
+		}
+		c.AddCommand(cmd)
+	}
+
+	handedDown := make(map[*Command][]commandFactory)
+	for _, f := range factories {
+		if len(f.parentPath) == 0 {
+			continue
+		}
+		var next *Command
+		for _, sub := range c.commands {
+			if sub.Name() == f.parentPath[0] {
+				next = sub
+				break
+			}
+		}
+		if next == nil {
+			fail(c, fmt.Errorf("parent command %q of a registered command not found in %q",
+				strings.Join(f.parentPath, " "), c.CommandPath()))
+			continue
+		}
+		handedDown[next] = append(handedDown[next], commandFactory{f.parentPath[1:], f.factory})
+	}
+
+	for _, sub := range c.commands {
+		sub.commandFactories = append(handedDown[sub], sub.commandFactories...)
+		sub.resolveCommands(fail)
+	}
+}
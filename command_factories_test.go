@@ -0,0 +1,160 @@
+package cobra
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRegisteredCommands(t *testing.T) {
+	var calls []string
+	factory := func(use string, aliases ...string) CommandFactory {
+		return func() *Command {
+			calls = append(calls, use)
+			return &Command{Use: use, Aliases: aliases, Short: "The " + use + " command", Run: emptyRun}
+		}
+	}
+
+	rootCmd := &Command{Use: "factories-root", Run: emptyRun}
+	userCmd := &Command{Use: "user", Run: emptyRun}
+	rootCmd.AddCommand(userCmd)
+
+	// Called from the root down, the factories of a command being called in
+	// their order of registration, the ones handed down by its parent first
+	rootCmd.RegisterCommandFactory("group", factory("list"))
+	rootCmd.RegisterCommandFactory("user", factory("export"))
+	rootCmd.RegisterCommandFactory("", factory("group"))
+	userCmd.RegisterCommandFactory("export", factory("csv"))
+	RegisterCommandFactory("factories-root user", factory("import"))
+	RegisterCommandFactory("other-root", factory("ignored"))
+
+	output, err := executeCommand(rootCmd, "user", "export", "csv")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output != "" {
+		t.Errorf("Unexpected output %q", output)
+	}
+	expected := []string{"group", "import", "export", "csv", "list"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("Expected the factories to be called in the order %v, got %v", expected, calls)
+	}
+
+	// The factories are called once
+	if _, err := executeCommand(rootCmd, "group", "list"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(calls) != len(expected) {
+		t.Errorf("Expected the factories to be called once, got %v", calls)
+	}
+
+	// The registered commands are completed like the others
+	output, err = executeCommand(rootCmd, ShellCompNoDescRequestCmd, "user", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "export\nimport\n")
+}
+
+func TestRegisteredCommandsErrors(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.AddCommand(&Command{Use: "status", Aliases: []string{"st"}, Run: emptyRun})
+	var calls []string
+	factory := func(use string, aliases ...string) CommandFactory {
+		return func() *Command {
+			calls = append(calls, use)
+			return &Command{Use: use, Aliases: aliases, Run: emptyRun}
+		}
+	}
+	rootCmd.RegisterCommandFactory("", factory("stat", "st"))
+	rootCmd.RegisterCommandFactory("", factory("deploy"))
+	rootCmd.RegisterCommandFactory("", factory("deploy"))
+	rootCmd.RegisterCommandFactory("missing", factory("sub"))
+
+	// The commands which can't be added are reported as warnings
+	output, err := executeCommand(rootCmd, "deploy")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "Warning: registered command \"root stat\" conflicts with the command \"root status\": \"st\" is already used\n" +
+		"Warning: registered command \"root deploy\" conflicts with the command \"root deploy\": \"deploy\" is already used\n" +
+		"Warning: parent command \"missing\" of a registered command not found in \"root\"\n"
+	if output != expected {
+		t.Errorf("Expected output %q, got %q", expected, output)
+	}
+
+	// The other commands are added
+	if cmd, _, err := rootCmd.Find([]string{"deploy"}); err != nil || cmd.Name() != "deploy" {
+		t.Errorf("Expected the deploy command, got %v, %v", cmd, err)
+	}
+	if !reflect.DeepEqual(calls, []string{"stat", "deploy", "deploy"}) {
+		t.Errorf("Unexpected calls of the factories %v", calls)
+	}
+
+	// The errors are returned by every resolution
+	err = rootCmd.ResolveCommands()
+	if err == nil || err.Error() != strings.TrimSuffix(strings.Replace(expected, "Warning: ", "", -1), "\n") {
+		t.Errorf("Expected the errors of the execution, got %v", err)
+	}
+	output, err = executeCommand(rootCmd, "deploy")
+	if err != nil || output != "" {
+		t.Errorf("Expected the warnings to be printed once, got %q, %v", output, err)
+	}
+
+	rootCmd.RegisterCommandFactory("", factory("status"))
+	rootCmd.RegisterCommandFactory("", func() *Command { return nil })
+	err = rootCmd.ResolveCommands()
+	if err == nil || !strings.Contains(err.Error(), `"status" is already used`) {
+		t.Errorf("Expected an error for the status command, got %v", err)
+	}
+	if !strings.HasSuffix(err.Error(), `a command factory registered for "root" returned no command`) {
+		t.Errorf("Expected an error for the factory returning nil, got %v", err)
+	}
+
+	rootCmd = &Command{Use: "root", Run: emptyRun}
+	rootCmd.AddCommand(&Command{Use: "user", Run: emptyRun})
+	rootCmd.RegisterCommandFactory("user missing", factory("sub"))
+	err = rootCmd.ResolveCommands()
+	expected = `parent command "missing" of a registered command not found in "root user"`
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected error %q, got %v", expected, err)
+	}
+
+	// Lint reports them as well, whatever resolved the tree first
+	rootCmd = &Command{Use: "root", Short: "The root", Run: emptyRun}
+	rootCmd.SetErr(new(bytes.Buffer))
+	rootCmd.RegisterCommandFactory("missing", factory("sub"))
+	for i := 0; i < 2; i++ {
+		issues := Lint(rootCmd)
+		if len(issues) != 1 || issues[0].String() != `root: parent command "missing" of a registered command not found in "root" (registered-command)` {
+			t.Errorf("Expected the missing parent to be reported, got %v", issues)
+		}
+	}
+	if err := rootCmd.ResolveCommands(); err == nil {
+		t.Error("Expected the missing parent to be reported after Lint")
+	}
+}
+
+func TestRegisteredCommandsOfRegisteredCommands(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	userCmd := &Command{Use: "user", Run: emptyRun}
+	rootCmd.AddCommand(userCmd)
+
+	// The parent of list is registered on user, below the command of the
+	// registration of list
+	rootCmd.RegisterCommandFactory("user group", func() *Command {
+		return &Command{Use: "list", Run: emptyRun}
+	})
+	userCmd.RegisterCommandFactory("", func() *Command {
+		return &Command{Use: "group", Run: emptyRun}
+	})
+
+	if err := rootCmd.ResolveCommands(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cmd, _, err := rootCmd.Find([]string{"user", "group", "list"})
+	if err != nil || cmd.CommandPath() != "root user group list" {
+		t.Errorf("Expected the command root user group list, got %v, %v", cmd, err)
+	}
+}
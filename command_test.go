@@ -314,6 +314,93 @@ func TestAliasPrefixMatching(t *testing.T) {
 	EnablePrefixMatching = false
 }
 
+func TestCommandPrefixMatching(t *testing.T) {
+	rootCmd := &Command{Use: "root", Args: NoArgs, Run: emptyRun}
+	configCmd := &Command{Use: "config", Args: NoArgs, Run: emptyRun, PrefixMatching: true}
+	var setCmdArgs []string
+	setCmd := &Command{
+		Use:  "set",
+		Args: ExactArgs(1),
+		Run:  func(_ *Command, args []string) { setCmdArgs = args },
+	}
+	configCmd.AddCommand(setCmd, &Command{Use: "view", Run: emptyRun})
+	rootCmd.AddCommand(configCmd)
+
+	// Prefix matching is enabled for config and its descendants only
+	if _, err := executeCommand(rootCmd, "conf", "s", "one"); err == nil {
+		t.Error("Expected an error without prefix matching on root")
+	}
+
+	_, err := executeCommand(rootCmd, "config", "s", "one")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(setCmdArgs, []string{"one"}) {
+		t.Errorf("setCmdArgs expected: %v, got: %v", []string{"one"}, setCmdArgs)
+	}
+}
+
+func TestAmbiguousPrefix(t *testing.T) {
+	rootCmd := &Command{Use: "root", Args: ArbitraryArgs, Run: emptyRun, PrefixMatching: true}
+	rootCmd.AddCommand(
+		&Command{Use: "serve", Run: emptyRun},
+		&Command{Use: "configure", Aliases: []string{"settings", "cfg"}, Run: emptyRun},
+		&Command{Use: "set", Run: emptyRun},
+		&Command{Use: "delete", Run: emptyRun})
+
+	for _, traverse := range []bool{false, true} {
+		rootCmd.TraverseChildren = traverse
+		output, err := executeCommand(rootCmd, "se", "arg")
+		prefixErr, ok := err.(*AmbiguousPrefixError)
+		if !ok {
+			t.Fatalf("Expected an AmbiguousPrefixError, got %T: %v", err, err)
+		}
+		expected := []string{"serve", "set", "settings"}
+		if prefixErr.Prefix != "se" || prefixErr.Command != rootCmd || !reflect.DeepEqual(prefixErr.Matches, expected) {
+			t.Errorf("Unexpected error: %#v", prefixErr)
+		}
+		checkStringContains(t, output, `Error: ambiguous command "se" for "root", it could be: serve, set, settings`)
+	}
+}
+
+func TestFlagAbbreviations(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun, FlagAbbreviations: true}
+	namespace := rootCmd.PersistentFlags().String("namespace", "", "namespace")
+	verbose := rootCmd.PersistentFlags().Bool("verbose", false, "verbose")
+	var childArgs []string
+	childCmd := &Command{Use: "child", Run: func(_ *Command, args []string) { childArgs = args }}
+	name := childCmd.Flags().StringP("name", "n", "", "name")
+	rootCmd.AddCommand(childCmd)
+
+	_, err := executeCommand(rootCmd, "--verb", "child", "--names", "x", "--namesp=ns", "-n", "--ver", "arg", "--", "--na")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if *namespace != "ns" || !*verbose || *name != "--ver" {
+		t.Errorf("Unexpected flag values: namespace %q, verbose %v, name %q", *namespace, *verbose, *name)
+	}
+	if !reflect.DeepEqual(childArgs, []string{"arg", "--na"}) {
+		t.Errorf("childArgs expected: %v, got: %v", []string{"arg", "--na"}, childArgs)
+	}
+
+	_, err = executeCommand(rootCmd, "child", "--na=x")
+	prefixErr, ok := err.(*AmbiguousPrefixError)
+	if !ok {
+		t.Fatalf("Expected an AmbiguousPrefixError, got %T: %v", err, err)
+	}
+	if !reflect.DeepEqual(prefixErr.Matches, []string{"--name", "--namespace"}) {
+		t.Errorf("Unexpected matches: %v", prefixErr.Matches)
+	}
+	expected := `ambiguous flag "--na" for "root child", it could be: --name, --namespace`
+	if err.Error() != expected {
+		t.Errorf("Expected: %q, got: %q", expected, err.Error())
+	}
+
+	rootCmd.FlagAbbreviations = false
+	_, err = executeCommand(rootCmd, "child", "--names", "ns")
+	checkStringContains(t, err.Error(), "unknown flag: --names")
+}
+
 // TestChildSameName checks the correct behaviour of cobra in cases,
 // when an application with name "foo" and with subcommand "foo"
 // is executed with args "foo foo".
@@ -1202,6 +1289,132 @@ func TestSuggestions(t *testing.T) {
 	}
 }
 
+func TestSuggestionsNestedAndAliases(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	configCmd := &Command{Use: "config", Run: emptyRun}
+	configCmd.AddCommand(&Command{Use: "set", Run: emptyRun}, &Command{Use: "view", Run: emptyRun})
+	rootCmd.AddCommand(configCmd,
+		&Command{Use: "sets", Run: emptyRun},
+		&Command{Use: "remove", Aliases: []string{"rm"}, Hidden: true, Run: emptyRun},
+		&Command{Use: "delete", Aliases: []string{"del"}, Run: emptyRun})
+
+	tests := []struct {
+		typo        string
+		nested      bool
+		aliases     bool
+		suggestions []string
+	}{
+		{"set", false, false, []string{"sets"}},
+		{"set", true, false, []string{"config set", "sets"}},
+		{"veiw", true, false, []string{"config view"}},
+		{"rn", false, false, []string{}},
+		{"rn", false, true, []string{"rm"}},
+		{"delt", false, false, []string{"delete"}},
+		{"delt", false, true, []string{"del", "delete"}},
+	}
+
+	for _, tc := range tests {
+		rootCmd.SuggestNestedCommands = tc.nested
+		rootCmd.SuggestAliases = tc.aliases
+		if got := rootCmd.SuggestionsFor(tc.typo); !reflect.DeepEqual(got, tc.suggestions) {
+			t.Errorf("%q (nested: %v, aliases: %v): expected %v, got %v", tc.typo, tc.nested, tc.aliases, tc.suggestions, got)
+		}
+	}
+
+	rootCmd.SuggestNestedCommands = true
+	output, _ := executeCommand(rootCmd, "veiw")
+	checkStringContains(t, output, "Did you mean this?\n\tconfig view\n")
+}
+
+func TestSuggestionsDistanceFunc(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.AddCommand(&Command{Use: "times", Run: emptyRun}, &Command{Use: "tines", Run: emptyRun})
+	rootCmd.Flags().Bool("times", false, "times")
+
+	// Ranked by distance
+	if got := rootCmd.SuggestionsFor("tines"); !reflect.DeepEqual(got, []string{"tines", "times"}) {
+		t.Errorf("expected suggestions ranked by distance, got %v", got)
+	}
+
+	// Only the first letter matters
+	rootCmd.SuggestionsDistanceFunc = func(typedName, candidate string) int {
+		if typedName[0] == candidate[0] {
+			return 0
+		}
+		return 10
+	}
+	if got := rootCmd.SuggestionsFor("txxxx"); !reflect.DeepEqual(got, []string{"times", "tines"}) {
+		t.Errorf("expected suggestions from the distance function, got %v", got)
+	}
+	if got := rootCmd.FlagSuggestionsFor("--txxxx"); !reflect.DeepEqual(got, []string{"--times"}) {
+		t.Errorf("expected flag suggestions from the distance function, got %v", got)
+	}
+}
+
+func TestFlagSuggestions(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.PersistentFlags().StringP("namespace", "n", "", "namespace")
+	childCmd := &Command{Use: "child", Run: emptyRun}
+	childCmd.Flags().Bool("all", false, "all")
+	childCmd.Flags().String("secret", "", "secret")
+	_ = childCmd.Flags().MarkHidden("secret")
+	rootCmd.AddCommand(childCmd)
+
+	tests := []struct {
+		args        []string
+		flag        string
+		suggestions []string
+	}{
+		{[]string{"child", "--namspace", "x"}, "--namspace", []string{"--namespace"}},
+		{[]string{"child", "--name=x"}, "--name", []string{"--namespace"}},
+		{[]string{"child", "--al"}, "--al", []string{"--all"}},
+		{[]string{"child", "-N", "x"}, "-N", []string{"-n"}},
+		{[]string{"child", "-all"}, "-a", []string{"--all"}},
+		{[]string{"child", "--secrt"}, "--secrt", nil},
+		{[]string{"child", "--foo"}, "--foo", nil},
+		{[]string{"child", "-x"}, "-x", nil},
+	}
+
+	for _, tc := range tests {
+		for _, suggestionsDisabled := range []bool{true, false} {
+			rootCmd.DisableSuggestions = suggestionsDisabled
+
+			_, err := executeCommand(rootCmd, tc.args...)
+			flagErr, ok := err.(*UnknownFlagError)
+			if !ok {
+				t.Fatalf("%v: expected an UnknownFlagError, got %T: %v", tc.args, err, err)
+			}
+			if flagErr.Flag != tc.flag {
+				t.Errorf("%v: expected flag %q, got %q", tc.args, tc.flag, flagErr.Flag)
+			}
+			expected := tc.suggestions
+			if suggestionsDisabled {
+				expected = nil
+			}
+			if !reflect.DeepEqual(flagErr.Suggestions, expected) {
+				t.Errorf("%v: expected suggestions %v, got %v", tc.args, expected, flagErr.Suggestions)
+			}
+			if len(expected) > 0 {
+				checkStringContains(t, err.Error(), "\n\nDid you mean "+strings.Join(expected, " or ")+"?")
+			} else {
+				checkStringOmits(t, err.Error(), "Did you mean")
+			}
+		}
+	}
+}
+
+func TestFlagSuggestionsMinimumDistance(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.Flags().String("namespace", "", "namespace")
+
+	_, err := executeCommand(rootCmd, "--nmaspcae")
+	checkStringOmits(t, err.Error(), "Did you mean")
+
+	rootCmd.SuggestionsMinimumDistance = 4
+	_, err = executeCommand(rootCmd, "--nmaspcae")
+	checkStringContains(t, err.Error(), "Did you mean --namespace?")
+}
+
 func TestRemoveCommand(t *testing.T) {
 	rootCmd := &Command{Use: "root", Args: NoArgs, Run: emptyRun}
 	childCmd := &Command{Use: "child", Run: emptyRun}
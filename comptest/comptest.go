@@ -0,0 +1,195 @@
+// Package comptest helps testing the completions of cobra programs.
+//
+// It requests the completions of a command-line from a command tree the
+// same way the shell completion scripts do, through the hidden __complete
+// command, and parses the result into completion choices, descriptions,
+// active help hints and ShellCompDirective values.
+package comptest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// activeHelpMarker prefixes the active help hints in the completion output.
+var activeHelpMarker = cobra.AppendActiveHelp(nil, "")[0]
+
+// Result holds the parsed output of a completion request.
+type Result struct {
+	// Completions are the completion choices, without their descriptions.
+	Completions []string
+	// Descriptions are the descriptions of the completion choices, in the
+	// same order as Completions.  A choice without description has an
+	// empty description.
+	Descriptions []string
+	// ActiveHelp are the active help hints, in the order they were given.
+	ActiveHelp []string
+	// Directive is the directive returned with the completions.
+	Directive cobra.ShellCompDirective
+}
+
+// Directives returns the individual directives set in the directive of the
+// result, from the lowest to the highest value.  It returns an empty list
+// for ShellCompDirectiveDefault.
+func (r *Result) Directives() []cobra.ShellCompDirective {
+	var directives []cobra.ShellCompDirective
+	for d := cobra.ShellCompDirectiveError; d > 0 && d <= r.Directive; d <<= 1 {
+		if r.Directive&d != 0 {
+			directives = append(directives, d)
+		}
+	}
+	return directives
+}
+
+// Complete returns the completions of the command tree of root for the
+// command-line line, as if the user pressed TAB at the end of the line.
+// See CompleteAt().
+func Complete(root *cobra.Command, line string) (*Result, error) {
+	return CompleteAt(root, line, len(line))
+}
+
+// CompleteAt returns the completions of the command tree of root for the
+// command-line line, as if the user pressed TAB with the cursor at the byte
+// offset cursor.  The word under the cursor is completed up to the cursor,
+// and the words following it are used for the flags they set.
+// The first word of the line is the program name and is ignored.
+// The line is split into words following the quoting rules of the shells:
+// single quotes, double quotes and backslashes.
+//
+// The arguments and the outputs of root are reset to their defaults once
+// the completions are obtained.
+//
+// Example:
+//   res, err := comptest.CompleteAt(rootCmd, "prog get --output js pods", 20)
+//   // res.Completions is []string{"json"}
+func CompleteAt(root *cobra.Command, line string, cursor int) (*Result, error) {
+	if cursor < 0 || cursor > len(line) {
+		return nil, fmt.Errorf("cursor %d out of the command-line of length %d", cursor, len(line))
+	}
+	words := splitWords(line[:cursor])
+	var args []string
+	for _, w := range words {
+		args = append(args, w.text)
+	}
+	if len(words) == 0 || words[len(words)-1].end < cursor {
+		// The cursor follows a blank: the word to complete is empty
+		args = append(args, "")
+	}
+	if len(args) < 2 {
+		return nil, errors.New("the command-line must start with the program name")
+	}
+	// The word under the cursor is replaced by the completion,
+	// only the words starting after the cursor are kept
+	index := len(args) - 2
+	for _, w := range splitWords(line) {
+		if w.start > cursor {
+			args = append(args, w.text)
+		}
+	}
+
+	out := new(bytes.Buffer)
+	root.SetArgs(append([]string{cobra.ShellCompRequestCmd, fmt.Sprintf("%s=%d", cobra.ShellCompCursorArg, index)}, args[1:]...))
+	root.SetOut(out)
+	root.SetErr(ioutil.Discard)
+	defer func() {
+		root.SetArgs(nil)
+		root.SetOut(nil)
+		root.SetErr(nil)
+	}()
+
+	if err := root.Execute(); err != nil {
+		return nil, err
+	}
+	return Parse(out.String())
+}
+
+// Parse parses the output of the __complete command of a cobra program.
+// It can be used to test the completions of a compiled program.
+func Parse(output string) (*Result, error) {
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	last := lines[len(lines)-1]
+	if !strings.HasPrefix(last, ":") {
+		return nil, fmt.Errorf("missing directive in the completion output %q", output)
+	}
+	directive, err := strconv.Atoi(last[1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid directive %q: %v", last, err)
+	}
+
+	res := &Result{Directive: cobra.ShellCompDirective(directive)}
+	for _, line := range lines[:len(lines)-1] {
+		if strings.HasPrefix(line, activeHelpMarker) {
+			res.ActiveHelp = append(res.ActiveHelp, strings.TrimPrefix(line, activeHelpMarker))
+			continue
+		}
+		comp, desc := line, ""
+		if i := strings.Index(line, "\t"); i >= 0 {
+			comp, desc = line[:i], line[i+1:]
+		}
+		res.Completions = append(res.Completions, comp)
+		res.Descriptions = append(res.Descriptions, desc)
+	}
+	return res, nil
+}
+
+// word is a word of a command-line, with the byte offsets where it starts
+// and ends in the command-line.
+type word struct {
+	text       string
+	start, end int
+}
+
+// splitWords splits a command-line into words.  A word with an unterminated
+// quote ends with the command-line.
+func splitWords(line string) []word {
+	var words []word
+	var text strings.Builder
+	start := -1
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if start < 0 && c != ' ' && c != '\t' {
+			start = i
+		}
+		switch {
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				text.WriteByte(c)
+			}
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+			} else if c == '\\' && i+1 < len(line) && strings.IndexByte("\"\\$`", line[i+1]) >= 0 {
+				i++
+				text.WriteByte(line[i])
+			} else {
+				text.WriteByte(c)
+			}
+		case c == ' ' || c == '\t':
+			if start >= 0 {
+				words = append(words, word{text.String(), start, i})
+				text.Reset()
+				start = -1
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '\\' && i+1 < len(line):
+			i++
+			text.WriteByte(line[i])
+		default:
+			text.WriteByte(c)
+		}
+	}
+	if start >= 0 {
+		words = append(words, word{text.String(), start, len(line)})
+	}
+	return words
+}
@@ -0,0 +1,153 @@
+package comptest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func emptyRun(*cobra.Command, []string) {}
+
+func newTestCmd() *cobra.Command {
+	rootCmd := &cobra.Command{Use: "root", Run: emptyRun}
+	getCmd := &cobra.Command{
+		Use:   "get",
+		Short: "Display resources",
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return cobra.AppendActiveHelp(nil, "Only one resource"), cobra.ShellCompDirectiveNoFileComp
+			}
+			return []string{"pods\tThe pods", "nodes"}, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+		},
+		Run: emptyRun,
+	}
+	var output string
+	getCmd.Flags().VarP(cobra.NewEnumValue(&output, "json", "json", "yaml"), "output", "o", "output format")
+	getCmd.Flags().Bool("all", false, "all resources")
+	rootCmd.AddCommand(getCmd, &cobra.Command{Use: "delete", Short: "Delete resources", Run: emptyRun})
+	return rootCmd
+}
+
+func TestComplete(t *testing.T) {
+	rootCmd := newTestCmd()
+
+	res, err := Complete(rootCmd, "root ")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := &Result{
+		Completions:  []string{"delete", "get", "help"},
+		Descriptions: []string{"Delete resources", "Display resources", "Help about any command"},
+		Directive:    cobra.ShellCompDirectiveNoFileComp,
+	}
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("expected: %#v\ngot: %#v", expected, res)
+	}
+
+	res, err = Complete(rootCmd, "root get ")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected = &Result{
+		Completions:  []string{"pods", "nodes"},
+		Descriptions: []string{"The pods", ""},
+		Directive:    cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace,
+	}
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("expected: %#v\ngot: %#v", expected, res)
+	}
+	directives := []cobra.ShellCompDirective{cobra.ShellCompDirectiveNoSpace, cobra.ShellCompDirectiveNoFileComp}
+	if !reflect.DeepEqual(res.Directives(), directives) {
+		t.Errorf("expected: %v\ngot: %v", directives, res.Directives())
+	}
+
+	res, err = Complete(rootCmd, "root get pods ")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(res.Completions) != 0 || !reflect.DeepEqual(res.ActiveHelp, []string{"Only one resource"}) {
+		t.Errorf("expected only active help, got: %#v", res)
+	}
+}
+
+func TestCompleteAt(t *testing.T) {
+	rootCmd := newTestCmd()
+
+	// The rest of the word under the cursor is ignored
+	line := "root get --output yam pods"
+	res, err := CompleteAt(rootCmd, line, len("root get --output y"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(res.Completions, []string{"yaml"}) {
+		t.Errorf("expected: %v\ngot: %v", []string{"yaml"}, res.Completions)
+	}
+	if len(res.Directives()) != 1 || res.Directives()[0] != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("expected: %v\ngot: %v", cobra.ShellCompDirectiveNoFileComp, res.Directives())
+	}
+
+	// The arguments following the cursor are not given to the completion function
+	res, err = CompleteAt(rootCmd, "root get  pods", len("root get "))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(res.Completions, []string{"pods", "nodes"}) {
+		t.Errorf("expected: %v\ngot: %v", []string{"pods", "nodes"}, res.Completions)
+	}
+
+	// The flags following the cursor are considered as set
+	res, err = CompleteAt(rootCmd, "root get - -o json", len("root get -"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(res.Completions, []string{"--all"}) {
+		t.Errorf("expected: %v\ngot: %v", []string{"--all"}, res.Completions)
+	}
+
+	if _, err := CompleteAt(rootCmd, line, len(line)+1); err == nil {
+		t.Error("expected an error for a cursor out of the command-line")
+	}
+	if _, err := CompleteAt(rootCmd, line, 0); err == nil {
+		t.Error("expected an error for a command-line without program name")
+	}
+}
+
+func TestParse(t *testing.T) {
+	res, err := Parse("a\tfirst\nb\n_activeHelp_ hint\n:9\n")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := &Result{
+		Completions:  []string{"a", "b"},
+		Descriptions: []string{"first", ""},
+		ActiveHelp:   []string{"hint"},
+		Directive:    cobra.ShellCompDirectiveError | cobra.ShellCompDirectiveFilterFileExt,
+	}
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("expected: %#v\ngot: %#v", expected, res)
+	}
+
+	if _, err := Parse("a\nb\n"); err == nil {
+		t.Error("expected an error for an output without directive")
+	}
+}
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		line  string
+		words []word
+	}{
+		{"", nil},
+		{"root", []word{{"root", 0, 4}}},
+		{"root  ", []word{{"root", 0, 4}}},
+		{"root get 'a b' \"c \\\" d\" e\\ f", []word{{"root", 0, 4}, {"get", 5, 8}, {"a b", 9, 14}, {"c \" d", 15, 23}, {"e f", 24, 28}}},
+		{"root --name=\"unterminated ", []word{{"root", 0, 4}, {"--name=unterminated ", 5, 26}}},
+		{"root ''", []word{{"root", 0, 4}, {"", 5, 7}}},
+	}
+	for _, tc := range tests {
+		if words := splitWords(tc.line); !reflect.DeepEqual(words, tc.words) {
+			t.Errorf("%q: expected: %v\ngot: %v", tc.line, tc.words, words)
+		}
+	}
+}
@@ -3,7 +3,9 @@ package cobra
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
 )
@@ -15,6 +17,14 @@ const (
 	// ShellCompNoDescRequestCmd is the name of the hidden command that is used to request
 	// completion results without their description.  It is used by the shell completion scripts.
 	ShellCompNoDescRequestCmd = "__completeNoDesc"
+	// ShellCompCursorArg, followed by an = and an index, can be given as the
+	// first argument of the hidden completion command to specify which of the
+	// following arguments must be completed, instead of the last one.
+	// A negative index counts from the end, -1 being the last argument.
+	// The arguments following the cursor are also used for their flags.
+	// For example:
+	//   <program> __complete __cursor=-3 get "" --namespace kube-system
+	ShellCompCursorArg = "__cursor"
 )
 
 // Global map of flag completion functions.
@@ -76,8 +86,8 @@ func (c *Command) RegisterFlagCompletionFunc(flagName string, f func(cmd *Comman
 	return nil
 }
 
-// Returns a string listing the different directive enabled in the specified parameter
-func (d ShellCompDirective) string() string {
+// String returns a string listing the different directives enabled in the specified parameter.
+func (d ShellCompDirective) String() string {
 	var directives []string
 	if d&ShellCompDirectiveError != 0 {
 		directives = append(directives, "ShellCompDirectiveError")
@@ -126,7 +136,12 @@ func (c *Command) initCompleteCmd(args []string) {
 			}
 
 			noDescriptions := (cmd.CalledAs() == ShellCompNoDescRequestCmd)
+			activeHelpEnabled := cmd.activeHelpEnabled()
 			for _, comp := range completions {
+				if isActiveHelp(comp) && !activeHelpEnabled {
+					// The user disabled active help for this program
+					continue
+				}
 				if noDescriptions {
 					// Remove any description that may be included following a tab character.
 					comp = strings.Split(comp, "\t")[0]
@@ -160,7 +175,7 @@ func (c *Command) initCompleteCmd(args []string) {
 
 			// Print some helpful info to stderr for the user to understand.
 			// Output from stderr must be ignored by the completion script.
-			fmt.Fprintf(finalCmd.ErrOrStderr(), "Completion ended with directive: %s\n", directive.string())
+			fmt.Fprintf(finalCmd.ErrOrStderr(), "Completion ended with directive: %s\n", directive.String())
 		},
 	}
 	c.AddCommand(completeCmd)
@@ -176,14 +191,16 @@ func (c *Command) initCompleteCmd(args []string) {
 }
 
 func (c *Command) getCompletions(args []string) (*Command, []string, ShellCompDirective, error) {
-	// The last argument, which is not completely typed by the user,
-	// should not be part of the list of arguments
-	toComplete := args[len(args)-1]
-	trimmedArgs := args[:len(args)-1]
+	// The argument being completed, which is not completely typed by the user,
+	// should not be part of the list of arguments.  Neither should the arguments
+	// following the cursor, which are only used for their flags.
+	trimmedArgs, toComplete, afterArgs, err := splitCompletionArgs(args)
+	if err != nil {
+		return c, []string{}, ShellCompDirectiveDefault, err
+	}
 
 	var finalCmd *Command
 	var finalArgs []string
-	var err error
 	// Find the real command for which completion must be performed
 	// check if we need to traverse here to parse local flags on parent commands
 	if c.Root().TraverseChildren {
@@ -196,6 +213,12 @@ func (c *Command) getCompletions(args []string) (*Command, []string, ShellCompDi
 		return c, []string{}, ShellCompDirectiveDefault, fmt.Errorf("Unable to find a command for arguments: %v", trimmedArgs)
 	}
 
+	// The plugins complete their own arguments
+	if finalCmd.IsPlugin() {
+		completions, directive := finalCmd.pluginCompletions(finalArgs, toComplete, afterArgs)
+		return finalCmd, completions, directive, nil
+	}
+
 	// Check if we are doing flag value completion before parsing the flags.
 	// This is important because if we are completing a flag value, we need to also
 	// remove the flag name argument from the list of finalArgs or else the parsing
@@ -211,6 +234,20 @@ func (c *Command) getCompletions(args []string) (*Command, []string, ShellCompDi
 		return finalCmd, []string{}, ShellCompDirectiveDefault, fmt.Errorf("Error while parsing flags from args %v: %s", finalArgs, err.Error())
 	}
 
+	// We only remove the flags from the arguments if DisableFlagParsing is not set.
+	// This is important for commands which have requested to do their own flag completion.
+	if !finalCmd.DisableFlagParsing {
+		finalArgs = finalCmd.Flags().Args()
+	}
+
+	// Also parse the flags following the cursor so that they are considered
+	// as set, while only keeping the arguments preceding the cursor.
+	// Errors are ignored since the command-line may not be complete yet,
+	// or may be for a sub-command which has not been typed yet.
+	if len(afterArgs) > 0 {
+		_ = finalCmd.ParseFlags(afterArgs)
+	}
+
 	if flag != nil {
 		// Check if we are completing a flag value subject to annotations
 		if validExts, present := flag.Annotations[BashCompFilenameExt]; present {
@@ -233,6 +270,26 @@ func (c *Command) getCompletions(args []string) (*Command, []string, ShellCompDi
 			// Directory completion
 			return finalCmd, []string{}, ShellCompDirectiveFilterDirs, nil
 		}
+
+		// Complete the static list of values of the flag, unless the program
+		// registered a completion function for it.
+		if values := flagValues(flag); len(values) > 0 && flagCompletionFunctions[flag] == nil {
+			var completions []string
+			for _, value := range values {
+				if strings.HasPrefix(value, toComplete) {
+					completions = append(completions, value)
+				}
+			}
+			return finalCmd, completions, ShellCompDirectiveNoFileComp, nil
+		}
+
+		// Complete the value according to the type of the flag, unless the
+		// program registered a completion function for it.
+		if flagCompletionFunctions[flag] == nil {
+			if completions, directive, ok := defaultFlagCompletion(flag, toComplete); ok {
+				return finalCmd, completions, directive, nil
+			}
+		}
 	}
 
 	// When doing completion of a flag name, as soon as an argument starts with
@@ -276,13 +333,7 @@ func (c *Command) getCompletions(args []string) (*Command, []string, ShellCompDi
 		return finalCmd, completions, directive, nil
 	}
 
-	// We only remove the flags from the arguments if DisableFlagParsing is not set.
-	// This is important for commands which have requested to do their own flag completion.
-	if !finalCmd.DisableFlagParsing {
-		finalArgs = finalCmd.Flags().Args()
-	}
-
-	var completions []string
+	var completions, activeHelp []string
 	directive := ShellCompDirectiveDefault
 	if flag == nil {
 		foundLocalNonPersistentFlag := false
@@ -311,10 +362,36 @@ func (c *Command) getCompletions(args []string) (*Command, []string, ShellCompDi
 					directive = ShellCompDirectiveNoFileComp
 				}
 			}
+			// Complete the aliases defined by the users
+			for _, alias := range finalCmd.UserAliases() {
+				if strings.HasPrefix(alias.Name, toComplete) {
+					completions = append(completions, fmt.Sprintf("%s\t%s", alias.Name, finalCmd.Translate("alias for %q", alias.Expansion())))
+				}
+				directive = ShellCompDirectiveNoFileComp
+			}
+			// Complete the plugins, leaving out the nested ones, e.g.
+			// "deploy prod", which complete their own arguments from the
+			// plugin of their first word, if any
+			for _, plugin := range finalCmd.Plugins() {
+				if !strings.Contains(plugin.Name, " ") && strings.HasPrefix(plugin.Name, toComplete) {
+					completions = append(completions, fmt.Sprintf("%s\t%s", plugin.Name, finalCmd.Translate("plugin %s", plugin.Path)))
+				}
+				directive = ShellCompDirectiveNoFileComp
+			}
 		}
 
 		// Complete required flags even without the '-' prefix
-		completions = append(completions, completeRequireFlags(finalCmd, toComplete)...)
+		requiredFlags := completeRequireFlags(finalCmd, toComplete)
+		completions = append(completions, requiredFlags...)
+
+		// Give hints about the missing required flags which are not already
+		// completed, and about arguments which will not be accepted.
+		if len(requiredFlags) == 0 {
+			activeHelp = append(activeHelp, requiredFlagsActiveHelp(finalCmd)...)
+		}
+		if len(finalArgs) > 0 || !finalCmd.HasAvailableSubCommands() {
+			activeHelp = append(activeHelp, argsActiveHelp(finalCmd, finalArgs, toComplete)...)
+		}
 
 		// Always complete ValidArgs, even if we are completing a subcommand name.
 		// This is for commands that have both subcommands and ValidArgs.
@@ -341,7 +418,7 @@ func (c *Command) getCompletions(args []string) (*Command, []string, ShellCompDi
 
 			// If there are ValidArgs specified (even if they don't match), we stop completion.
 			// Only one of ValidArgs or ValidArgsFunction can be used for a single command.
-			return finalCmd, completions, directive, nil
+			return finalCmd, append(completions, activeHelp...), directive, nil
 		}
 
 		// Let the logic continue so as to add any ValidArgsFunction completions,
@@ -364,7 +441,80 @@ func (c *Command) getCompletions(args []string) (*Command, []string, ShellCompDi
 		completions = append(completions, comps...)
 	}
 
-	return finalCmd, completions, directive, nil
+	return finalCmd, append(completions, activeHelp...), directive, nil
+}
+
+// durationUnits are the units completed after the number of a duration.
+var durationUnits = []string{"ms", "s", "m", "h"}
+
+// defaultFlagCompletion returns the completions of the value of a flag based
+// on its type, and false if there is no such completion or if the flag was
+// marked with MarkFlagNoDefaultCompletion().
+func defaultFlagCompletion(flag *pflag.Flag, toComplete string) ([]string, ShellCompDirective, bool) {
+	if _, present := flag.Annotations[FlagNoDefaultCompletionAnnotation]; present {
+		return nil, ShellCompDirectiveDefault, false
+	}
+
+	var completions []string
+	switch flag.Value.Type() {
+	case "bool":
+		// Only reached when the value is given with an =, e.g., --flag=<TAB>
+		for _, value := range []string{"true", "false"} {
+			if strings.HasPrefix(value, toComplete) {
+				completions = append(completions, value)
+			}
+		}
+	case "duration":
+		if len(toComplete) == 0 {
+			// Give some examples of the format
+			completions = []string{"30s", "5m", "1h"}
+			break
+		}
+		// Complete the units following the last number
+		if i := strings.LastIndexAny(toComplete, "0123456789"); i >= 0 {
+			for _, unit := range durationUnits {
+				value := toComplete[:i+1] + unit
+				if _, err := time.ParseDuration(value); err == nil && strings.HasPrefix(value, toComplete) {
+					completions = append(completions, value)
+				}
+			}
+		}
+	case "ip", "ipSlice":
+		completions = AppendActiveHelp(nil, "Expecting an IP address, e.g. 192.168.0.10 or fd00::10")
+	case "ipNet":
+		completions = AppendActiveHelp(nil, "Expecting a network in CIDR notation, e.g. 192.168.0.0/24 or fd00::/64")
+	case "ipMask":
+		completions = AppendActiveHelp(nil, "Expecting an IP mask, e.g. 255.255.255.0")
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64",
+		"float32", "float64", "count", "intSlice", "int32Slice", "int64Slice", "uintSlice",
+		"float32Slice", "float64Slice":
+		// Numbers cannot be completed, but file names should not be either
+	default:
+		return nil, ShellCompDirectiveDefault, false
+	}
+	return completions, ShellCompDirectiveNoFileComp, true
+}
+
+// splitCompletionArgs returns the arguments preceding the argument to
+// complete, the argument to complete and the arguments following it.
+// The argument to complete is the last one, unless its index is given by
+// a first argument of the form ShellCompCursorArg=<index>.
+func splitCompletionArgs(args []string) ([]string, string, []string, error) {
+	cursorPrefix := ShellCompCursorArg + "="
+	if !strings.HasPrefix(args[0], cursorPrefix) {
+		return args[:len(args)-1], args[len(args)-1], nil, nil
+	}
+
+	cursor := args[0]
+	args = args[1:]
+	index, err := strconv.Atoi(strings.TrimPrefix(cursor, cursorPrefix))
+	if err == nil && index < 0 {
+		index += len(args)
+	}
+	if err != nil || index < 0 || index >= len(args) {
+		return nil, "", nil, fmt.Errorf("Invalid cursor '%s' for arguments: %v", cursor, args)
+	}
+	return args[:index], args[index], args[index+1:], nil
 }
 
 func getFlagNameCompletions(flag *pflag.Flag, toComplete string) []string {
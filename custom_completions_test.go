@@ -2,6 +2,9 @@ package cobra
 
 import (
 	"bytes"
+	"fmt"
+	"net"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -1958,3 +1961,271 @@ func TestCompleteHelp(t *testing.T) {
 		t.Errorf("expected: %q, got: %q", expected, output)
 	}
 }
+
+func TestFlagValuesCompletionInGo(t *testing.T) {
+	rootCmd := &Command{
+		Use: "root",
+		Run: emptyRun,
+	}
+	var output string
+	rootCmd.Flags().VarP(NewEnumValue(&output, "json", "json\tJSON output", "yaml\tYAML output", "text"), "output", "o", "output format")
+	rootCmd.Flags().String("color", "", "color mode")
+	rootCmd.MarkFlagValues("color", "auto", "always", "never")
+
+	// Test that the enum values are completed
+	out, err := executeCommand(rootCmd, ShellCompNoDescRequestCmd, "--output", "")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		"json",
+		"yaml",
+		"text",
+		":4",
+		"Completion ended with directive: ShellCompDirectiveNoFileComp", ""}, "\n")
+
+	if out != expected {
+		t.Errorf("expected: %q, got: %q", expected, out)
+	}
+
+	// Test that the enum values are completed with descriptions and a prefix
+	out, err = executeCommand(rootCmd, ShellCompRequestCmd, "-o", "j")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expected = strings.Join([]string{
+		"json\tJSON output",
+		":4",
+		"Completion ended with directive: ShellCompDirectiveNoFileComp", ""}, "\n")
+
+	if out != expected {
+		t.Errorf("expected: %q, got: %q", expected, out)
+	}
+
+	// Test that the marked values are completed after an =
+	out, err = executeCommand(rootCmd, ShellCompNoDescRequestCmd, "--color=a")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expected = strings.Join([]string{
+		"auto",
+		"always",
+		":4",
+		"Completion ended with directive: ShellCompDirectiveNoFileComp", ""}, "\n")
+
+	if out != expected {
+		t.Errorf("expected: %q, got: %q", expected, out)
+	}
+}
+
+func TestFlagValuesWithCompletionFuncInGo(t *testing.T) {
+	rootCmd := &Command{
+		Use: "root",
+		Run: emptyRun,
+	}
+	rootCmd.Flags().String("color", "", "color mode")
+	rootCmd.MarkFlagValues("color", "auto", "always", "never")
+	rootCmd.RegisterFlagCompletionFunc("color", func(cmd *Command, args []string, toComplete string) ([]string, ShellCompDirective) {
+		return []string{"never"}, ShellCompDirectiveDefault
+	})
+
+	// Test that a registered completion function has precedence over the values
+	out, err := executeCommand(rootCmd, ShellCompNoDescRequestCmd, "--color", "")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		"never",
+		":0",
+		"Completion ended with directive: ShellCompDirectiveDefault", ""}, "\n")
+
+	if out != expected {
+		t.Errorf("expected: %q, got: %q", expected, out)
+	}
+}
+
+func TestFlagValuesInBashScript(t *testing.T) {
+	rootCmd := &Command{Use: "root", Args: NoArgs, Run: emptyRun}
+	var output string
+	rootCmd.Flags().Var(NewEnumValue(&output, "json", "json", "yaml"), "output", "output format")
+
+	buf := new(bytes.Buffer)
+	rootCmd.GenBashCompletion(buf)
+	out := buf.String()
+
+	check(t, out, `flags_with_completion+=("--output")`)
+	check(t, out, `flags_completion+=("__root_handle_go_custom_completion")`)
+}
+
+func TestCompletionWithCursorInGo(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	childCmd := &Command{
+		Use: "get",
+		ValidArgsFunction: func(cmd *Command, args []string, toComplete string) ([]string, ShellCompDirective) {
+			namespace, _ := cmd.Flags().GetString("namespace")
+			return []string{fmt.Sprintf("%s/%d", namespace, len(args))}, ShellCompDirectiveNoFileComp
+		},
+		Run: emptyRun,
+	}
+	childCmd.Flags().String("namespace", "default", "namespace")
+	childCmd.Flags().Bool("all", false, "all resources")
+	rootCmd.AddCommand(childCmd)
+
+	// Test that the flags following the cursor are considered
+	out, err := executeCommand(rootCmd, ShellCompNoDescRequestCmd, ShellCompCursorArg+"=1", "get", "", "--namespace", "kube-system")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		"kube-system/0",
+		":4",
+		"Completion ended with directive: ShellCompDirectiveNoFileComp", ""}, "\n")
+
+	if out != expected {
+		t.Errorf("expected: %q, got: %q", expected, out)
+	}
+
+	// Test that the index can count from the end and that the arguments following
+	// the cursor are not given to the completion function
+	out, err = executeCommand(rootCmd, ShellCompNoDescRequestCmd, ShellCompCursorArg+"=-4", "get", "pod", "", "pod", "--namespace", "kube-system")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expected = strings.Join([]string{
+		"kube-system/1",
+		":4",
+		"Completion ended with directive: ShellCompDirectiveNoFileComp", ""}, "\n")
+
+	if out != expected {
+		t.Errorf("expected: %q, got: %q", expected, out)
+	}
+
+	// Test that the flags following the cursor are not completed again
+	out, err = executeCommand(rootCmd, ShellCompNoDescRequestCmd, ShellCompCursorArg+"=1", "get", "--", "--namespace", "kube-system")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expected = strings.Join([]string{
+		"--all",
+		":4",
+		"Completion ended with directive: ShellCompDirectiveNoFileComp", ""}, "\n")
+
+	if out != expected {
+		t.Errorf("expected: %q, got: %q", expected, out)
+	}
+
+	// Test that the flags of a sub-command following the cursor do not prevent
+	// the completion of the sub-command
+	out, err = executeCommand(rootCmd, ShellCompNoDescRequestCmd, ShellCompCursorArg+"=0", "g", "--namespace", "kube-system")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expected = strings.Join([]string{
+		"get",
+		":4",
+		"Completion ended with directive: ShellCompDirectiveNoFileComp", ""}, "\n")
+
+	if out != expected {
+		t.Errorf("expected: %q, got: %q", expected, out)
+	}
+
+	// Test that an invalid cursor gives no completion
+	out, err = executeCommand(rootCmd, ShellCompNoDescRequestCmd, ShellCompCursorArg+"=2", "get", "")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expected = strings.Join([]string{
+		":0",
+		"Completion ended with directive: ShellCompDirectiveDefault", ""}, "\n")
+
+	if out != expected {
+		t.Errorf("expected: %q, got: %q", expected, out)
+	}
+}
+
+func TestDefaultFlagCompletionInGo(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.Flags().Bool("verbose", false, "verbose output")
+	rootCmd.Flags().Duration("timeout", 0, "timeout")
+	rootCmd.Flags().IP("address", nil, "address")
+	rootCmd.Flags().IPNet("network", net.IPNet{}, "network")
+	rootCmd.Flags().Int("replicas", 1, "replicas")
+	rootCmd.Flags().Float64("ratio", 0, "ratio")
+	rootCmd.Flags().Duration("interval", 0, "interval")
+	rootCmd.MarkFlagNoDefaultCompletion("interval")
+	rootCmd.Flags().Int("port", 0, "port")
+	rootCmd.RegisterFlagCompletionFunc("port", func(cmd *Command, args []string, toComplete string) ([]string, ShellCompDirective) {
+		return []string{"80", "443"}, ShellCompDirectiveNoFileComp
+	})
+
+	tests := []struct {
+		args     []string
+		expected []string
+	}{
+		// Bool flags are completed when given with an =
+		{[]string{"--verbose="}, []string{"true", "false", ":4"}},
+		{[]string{"--verbose=f"}, []string{"false", ":4"}},
+		// Bool flags without an = are followed by arguments
+		{[]string{"--verbose", ""}, []string{":0"}},
+		// Durations are completed with examples, then with units
+		{[]string{"--timeout", ""}, []string{"30s", "5m", "1h", ":4"}},
+		{[]string{"--timeout", "5"}, []string{"5ms", "5s", "5m", "5h", ":4"}},
+		{[]string{"--timeout=1h3"}, []string{"1h3ms", "1h3s", "1h3m", "1h3h", ":4"}},
+		{[]string{"--timeout", "5m"}, []string{"5ms", "5m", ":4"}},
+		{[]string{"--timeout", "x"}, []string{":4"}},
+		// IP addresses and networks are explained
+		{[]string{"--address", ""}, []string{"_activeHelp_ Expecting an IP address, e.g. 192.168.0.10 or fd00::10", ":4"}},
+		{[]string{"--network", "1"}, []string{"_activeHelp_ Expecting a network in CIDR notation, e.g. 192.168.0.0/24 or fd00::/64", ":4"}},
+		// Numbers are not completed, but neither are file names
+		{[]string{"--replicas", ""}, []string{":4"}},
+		{[]string{"--ratio", "0."}, []string{":4"}},
+		// Flags can opt out of the default completion
+		{[]string{"--interval", ""}, []string{":0"}},
+		// Completion functions have precedence
+		{[]string{"--port", ""}, []string{"80", "443", ":4"}},
+	}
+	for _, tc := range tests {
+		out, err := executeCommand(rootCmd, append([]string{ShellCompNoDescRequestCmd}, tc.args...)...)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		// Ignore the directive explanation on the last line
+		lines := strings.Split(out, "\n")
+		got := lines[:len(lines)-2]
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("%v: expected: %q, got: %q", tc.args, tc.expected, got)
+		}
+	}
+}
+
+func TestDefaultFlagCompletionInBashScript(t *testing.T) {
+	rootCmd := &Command{Use: "root", Args: NoArgs, Run: emptyRun}
+	rootCmd.Flags().Duration("timeout", 0, "timeout")
+	rootCmd.Flags().Int("replicas", 1, "replicas")
+	rootCmd.Flags().Bool("verbose", false, "verbose output")
+	rootCmd.Flags().Int("port", 0, "port")
+	rootCmd.MarkFlagNoDefaultCompletion("port")
+	rootCmd.Flags().Int("count", 0, "count")
+	rootCmd.MarkFlagCustom("count", "__count_func")
+
+	buf := new(bytes.Buffer)
+	rootCmd.GenBashCompletion(buf)
+	out := buf.String()
+
+	check(t, out, `flags_with_completion+=("--timeout")`)
+	check(t, out, `flags_with_completion+=("--replicas")`)
+	check(t, out, `flags_completion+=("__root_handle_go_custom_completion")`)
+	check(t, out, `flags_completion+=("__count_func")`)
+	checkOmit(t, out, `flags_with_completion+=("--verbose")`)
+	checkOmit(t, out, `flags_with_completion+=("--port")`)
+}
@@ -0,0 +1,288 @@
+package cobra
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+// FlagDeprecationAnnotation is the annotation holding the deprecation of a
+// flag set with MarkFlagDeprecation(): its message, replacement and removal
+// version.
+const FlagDeprecationAnnotation = "cobra_annotation_flag_deprecation"
+
+// Deprecation describes the deprecation of a command or of a flag.
+type Deprecation struct {
+	// Message explains the deprecation, e.g. "the configuration file is
+	// now used".  It is optional.
+	Message string
+	// Replacement is the command path, e.g. "app config set", or the flag,
+	// e.g. "--namespace", to use instead.  It is optional.
+	Replacement string
+	// RemovalVersion is the version of the program in which the command or
+	// flag is removed, e.g. "2.0.0".  Using it fails once the version of
+	// the program reaches RemovalVersion.  It is optional.
+	RemovalVersion string
+}
+
+// DeprecationHook is called when a deprecated command or flag is used, after
+// the deprecation warning is printed.  It is given the command being executed,
+// the name of the deprecated command path or flag, e.g. "app old" or "--old",
+// and its deprecation.  An error returned by the hook stops the execution of
+// the command, e.g. to escalate the use of deprecated features to errors.
+type DeprecationHook func(cmd *Command, name string, d *Deprecation) error
+
+// SetDeprecationHook sets the hook called when a deprecated command or flag is
+// used with this command or its descendants.
+func (c *Command) SetDeprecationHook(hook DeprecationHook) {
+	c.deprecationHook = hook
+}
+
+// MarkFlagDeprecation deprecates the flag with the given name.  Unlike flags
+// deprecated with MarkDeprecated(), the flag is still shown in the help and
+// the documentation, with the deprecation notice appended to its usage.
+func (c *Command) MarkFlagDeprecation(name string, d Deprecation) error {
+	return c.markFlagDeprecation(c.Flags(), name, d)
+}
+
+// MarkPersistentFlagDeprecation deprecates the persistent flag with the given
+// name.  See MarkFlagDeprecation().
+func (c *Command) MarkPersistentFlagDeprecation(name string, d Deprecation) error {
+	return c.markFlagDeprecation(c.PersistentFlags(), name, d)
+}
+
+func (c *Command) markFlagDeprecation(flags *flag.FlagSet, name string, d Deprecation) error {
+	f := flags.Lookup(name)
+	if f == nil {
+		return errors.New(c.Translate("flag %q does not exist", name))
+	}
+	if err := flags.SetAnnotation(name, FlagDeprecationAnnotation, []string{d.Message, d.Replacement, d.RemovalVersion}); err != nil {
+		return err
+	}
+	f.Usage += " (" + c.Translate("deprecated: %s", c.deprecationText(&d)) + ")"
+	return nil
+}
+
+// flagDeprecation returns the deprecation of the flag set with
+// MarkFlagDeprecation(), or nil if it is not deprecated that way.
+func flagDeprecation(f *flag.Flag) *Deprecation {
+	values, ok := f.Annotations[FlagDeprecationAnnotation]
+	if !ok || len(values) != 3 {
+		return nil
+	}
+	return &Deprecation{Message: values[0], Replacement: values[1], RemovalVersion: values[2]}
+}
+
+// IsDeprecated returns true if the command is deprecated, with Deprecated or
+// with Deprecation.
+func (c *Command) IsDeprecated() bool {
+	return len(c.Deprecated) > 0 || c.Deprecation != nil
+}
+
+// DeprecationNotice returns the notice shown at the top of the help of a
+// deprecated command, or an empty string if the command is not deprecated.
+// It is used by the default help template and by the documentation generators.
+func (c *Command) DeprecationNotice() string {
+	var text string
+	switch {
+	case c.Deprecation != nil:
+		text = c.deprecationText(c.Deprecation)
+	case len(c.Deprecated) > 0:
+		text = c.Deprecated
+	default:
+		return ""
+	}
+	if text == "" {
+		return c.Translate("Deprecated.")
+	}
+	return c.Translate("Deprecated: %s", text)
+}
+
+// deprecationText returns the message, the replacement and the removal
+// version of the deprecation as one text.
+func (c *Command) deprecationText(d *Deprecation) string {
+	var parts []string
+	if d.Message != "" {
+		parts = append(parts, d.Message)
+	}
+	if d.Replacement != "" {
+		parts = append(parts, c.Translate("use %q instead", d.Replacement))
+	}
+	if d.RemovalVersion != "" {
+		parts = append(parts, c.Translate("it will be removed in version %s", d.RemovalVersion))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// checkCommandDeprecation warns about the use of the command if it is
+// deprecated, and returns an error if it has been removed or if the
+// deprecation hook returns one.
+func (c *Command) checkCommandDeprecation() error {
+	if len(c.Deprecated) > 0 && c.Deprecation == nil {
+		c.PrintErr(c.Translate("Command %q is deprecated, %s", c.Name(), c.Deprecated) + "\n")
+		return c.callDeprecationHook(c.CommandPath(), &Deprecation{Message: c.Deprecated})
+	}
+	if c.Deprecation == nil {
+		return nil
+	}
+	if c.isRemoved(c.Deprecation) {
+		return errors.New(c.Translate("command %q has been removed in version %s", c.CommandPath(), c.Deprecation.RemovalVersion) + c.replacementHint(c.Deprecation))
+	}
+	if text := c.deprecationText(c.Deprecation); text != "" {
+		c.PrintErr(c.Translate("Command %q is deprecated, %s", c.Name(), text) + "\n")
+	} else {
+		c.PrintErr(c.Translate("Command %q is deprecated", c.Name()) + "\n")
+	}
+	return c.callDeprecationHook(c.CommandPath(), c.Deprecation)
+}
+
+// checkFlagDeprecations warns about the use of the flags deprecated with
+// MarkFlagDeprecation(), and returns an error if one of them has been
+// removed or if the deprecation hook returns one.
+func (c *Command) checkFlagDeprecations() error {
+	var err error
+	c.Flags().Visit(func(f *flag.Flag) {
+		d := flagDeprecation(f)
+		if d == nil || err != nil {
+			return
+		}
+		if c.isRemoved(d) {
+			err = errors.New(c.Translate("flag --%s has been removed in version %s", f.Name, d.RemovalVersion) + c.replacementHint(d))
+			return
+		}
+		if text := c.deprecationText(d); text != "" {
+			c.PrintErr(c.Translate("Flag --%s is deprecated, %s", f.Name, text) + "\n")
+		} else {
+			c.PrintErr(c.Translate("Flag --%s is deprecated", f.Name) + "\n")
+		}
+		err = c.callDeprecationHook("--"+f.Name, d)
+	})
+	return err
+}
+
+// replacementHint returns the hint of the replacement of the removed command
+// or flag to append to the error, if there is one.
+func (c *Command) replacementHint(d *Deprecation) string {
+	if d.Replacement == "" {
+		return ""
+	}
+	return ", " + c.Translate("use %q instead", d.Replacement)
+}
+
+// callDeprecationHook calls the deprecation hook of the command or of its
+// closest parent having one.
+func (c *Command) callDeprecationHook(name string, d *Deprecation) error {
+	for p := c; p != nil; p = p.parent {
+		if p.deprecationHook != nil {
+			return p.deprecationHook(c, name, d)
+		}
+	}
+	return nil
+}
+
+// isRemoved returns true if the version of the program, given by the
+// Version of the root command or by the build information, has reached the
+// removal version of the deprecation.  The removal is only enforced when
+// both versions are numeric, so that the development builds, e.g. "dev",
+// aren't affected.
+func (c *Command) isRemoved(d *Deprecation) bool {
+	if d.RemovalVersion == "" {
+		return false
+	}
+	cmp, ok := compareVersions(c.Root().VersionInfo().Version, d.RemovalVersion)
+	return ok && cmp >= 0
+}
+
+// version is a parsed dotted numeric version, such as "v1.10.2-rc.1".
+type version struct {
+	numbers    []int
+	prerelease []string
+}
+
+// parseVersion parses a dotted numeric version with an optional "v" prefix,
+// pre-release suffix and build suffix, and returns false if v isn't one.
+func parseVersion(v string) (version, bool) {
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.Index(v, "+"); i >= 0 {
+		v = v[:i]
+	}
+	var parsed version
+	if i := strings.Index(v, "-"); i >= 0 {
+		parsed.prerelease = strings.Split(v[i+1:], ".")
+		v = v[:i]
+	}
+	for _, part := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return version{}, false
+		}
+		parsed.numbers = append(parsed.numbers, n)
+	}
+	return parsed, true
+}
+
+// compareVersions compares the dotted numeric versions a and b, such as
+// "v1.10.2", and returns -1, 0 or 1 if a is lower, equal or greater than b,
+// and false if one of them isn't a numeric version.  The missing numbers
+// are zeros, a pre-release, e.g. "2.0.0-rc.1", is lower than its release,
+// and the build suffixes are ignored.
+func compareVersions(a, b string) (int, bool) {
+	av, aok := parseVersion(a)
+	bv, bok := parseVersion(b)
+	if !aok || !bok {
+		return 0, false
+	}
+	for i := 0; i < len(av.numbers) || i < len(bv.numbers); i++ {
+		var an, bn int
+		if i < len(av.numbers) {
+			an = av.numbers[i]
+		}
+		if i < len(bv.numbers) {
+			bn = bv.numbers[i]
+		}
+		if an != bn {
+			return compareInts(an, bn), true
+		}
+	}
+
+	switch {
+	case av.prerelease == nil && bv.prerelease == nil:
+		return 0, true
+	case av.prerelease == nil:
+		return 1, true
+	case bv.prerelease == nil:
+		return -1, true
+	}
+	// The identifiers of the pre-releases are compared as numbers if they
+	// are numeric, and as strings otherwise
+	for i := 0; i < len(av.prerelease) && i < len(bv.prerelease); i++ {
+		ap, bp := av.prerelease[i], bv.prerelease[i]
+		an, aerr := strconv.Atoi(ap)
+		bn, berr := strconv.Atoi(bp)
+		switch {
+		case aerr == nil && berr == nil:
+			if an != bn {
+				return compareInts(an, bn), true
+			}
+		case ap != bp:
+			if ap < bp {
+				return -1, true
+			}
+			return 1, true
+		}
+	}
+	return compareInts(len(av.prerelease), len(bv.prerelease)), true
+}
+
+// compareInts returns -1, 0 or 1 if a is lower, equal or greater than b.
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,182 @@
+package cobra
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func executeCommandSplitOutput(root *Command, args ...string) (stdout, stderr string, err error) {
+	outBuf, errBuf := new(bytes.Buffer), new(bytes.Buffer)
+	root.SetOut(outBuf)
+	root.SetErr(errBuf)
+	root.SetArgs(args)
+	err = root.Execute()
+	return outBuf.String(), errBuf.String(), err
+}
+
+func TestCommandDeprecation(t *testing.T) {
+	rootCmd := &Command{Use: "root", Version: "1.5.0", Run: emptyRun}
+	oldCmd := &Command{
+		Use:   "old",
+		Short: "the old command",
+		Deprecation: &Deprecation{
+			Message:        "the old way is slow",
+			Replacement:    "root new",
+			RemovalVersion: "2.0.0",
+		},
+		Run: func(c *Command, _ []string) { c.Print("output") },
+	}
+	rootCmd.AddCommand(oldCmd, &Command{Use: "new", Run: emptyRun})
+
+	stdout, stderr, err := executeCommandSplitOutput(rootCmd, "old")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if stdout != "output" {
+		t.Errorf("Expected the warning to be omitted from stdout, got %q", stdout)
+	}
+	expected := "Command \"old\" is deprecated, the old way is slow, use \"root new\" instead, it will be removed in version 2.0.0\n"
+	if stderr != expected {
+		t.Errorf("Expected: %q, got: %q", expected, stderr)
+	}
+
+	// Still listed, with a notice in its help
+	output, _ := executeCommand(rootCmd, "--help")
+	checkStringContains(t, output, "the old command")
+	output, _ = executeCommand(rootCmd, "old", "--help")
+	checkStringContains(t, output, "Deprecated: the old way is slow, use \"root new\" instead, it will be removed in version 2.0.0\n\nthe old command\n")
+
+	// Removed
+	rootCmd.Version = "v2.0.0"
+	_, _, err = executeCommandSplitOutput(rootCmd, "old")
+	expected = `command "root old" has been removed in version 2.0.0, use "root new" instead`
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected: %q, got: %v", expected, err)
+	}
+}
+
+func TestLegacyDeprecatedCommandToStderr(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.AddCommand(&Command{Use: "old", Deprecated: "use new instead", Run: emptyRun})
+
+	stdout, stderr, err := executeCommandSplitOutput(rootCmd, "old")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if stdout != "" || stderr != "Command \"old\" is deprecated, use new instead\n" {
+		t.Errorf("Unexpected stdout %q and stderr %q", stdout, stderr)
+	}
+}
+
+func TestFlagDeprecation(t *testing.T) {
+	rootCmd := &Command{Use: "root", Version: "1.0.0", Run: emptyRun}
+	rootCmd.Flags().String("ns", "", "the namespace")
+	rootCmd.Flags().String("namespace", "", "the namespace")
+	if err := rootCmd.MarkFlagDeprecation("ns", Deprecation{Replacement: "--namespace", RemovalVersion: "1.2"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := rootCmd.MarkFlagDeprecation("unknown", Deprecation{}); err == nil {
+		t.Error("Expected an error for an unknown flag")
+	}
+
+	stdout, stderr, err := executeCommandSplitOutput(rootCmd, "--ns", "default")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	expected := "Flag --ns is deprecated, use \"--namespace\" instead, it will be removed in version 1.2\n"
+	if stdout != "" || stderr != expected {
+		t.Errorf("Unexpected stdout %q and stderr %q", stdout, stderr)
+	}
+
+	output, _ := executeCommand(rootCmd, "--help")
+	checkStringContains(t, output, "the namespace (deprecated: use \"--namespace\" instead, it will be removed in version 1.2)")
+
+	rootCmd.Version = "1.2.1"
+	_, _, err = executeCommandSplitOutput(rootCmd, "--ns", "default")
+	expected = `flag --ns has been removed in version 1.2, use "--namespace" instead`
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected: %q, got: %v", expected, err)
+	}
+}
+
+func TestDeprecationHook(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	childCmd := &Command{Use: "child", Deprecation: &Deprecation{}, Run: emptyRun}
+	childCmd.Flags().Bool("old", false, "old flag")
+	_ = childCmd.MarkFlagDeprecation("old", Deprecation{Message: "no longer needed"})
+	rootCmd.AddCommand(childCmd)
+
+	var used []string
+	rootCmd.SetDeprecationHook(func(cmd *Command, name string, d *Deprecation) error {
+		if cmd != childCmd {
+			t.Errorf("Expected the hook to be given the executed command, got %q", cmd.Name())
+		}
+		used = append(used, name)
+		if d.Message == "no longer needed" {
+			return errors.New("deprecated flag used")
+		}
+		return nil
+	})
+
+	_, stderr, err := executeCommandSplitOutput(rootCmd, "child", "--old")
+	if err == nil || err.Error() != "deprecated flag used" {
+		t.Errorf("Expected the error of the hook, got %v", err)
+	}
+	checkStringContains(t, stderr, "Command \"child\" is deprecated\n")
+	checkStringContains(t, stderr, "Flag --old is deprecated, no longer needed\n")
+	if len(used) != 2 || used[0] != "root child" || used[1] != "--old" {
+		t.Errorf("Unexpected deprecated uses: %v", used)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"v1.2", "1.2.0", 0},
+		{"1.10.0", "1.9.0", 1},
+		{"1.9.9", "2.0.0", -1},
+		{"2.0.0-rc.1", "2.0.0", -1},
+		{"2.0.0-rc1", "2.0.0-rc2", -1},
+		{"2.0.0-rc.10", "2.0.0-rc.9", 1},
+		{"2.0.0-rc", "2.0.0-rc.1", -1},
+		{"2.0.1-rc.1", "2.0.0", 1},
+		{"1.0.0+build", "1.0.1", -1},
+	}
+	for _, tc := range tests {
+		if got, ok := compareVersions(tc.a, tc.b); !ok || got != tc.expected {
+			t.Errorf("compareVersions(%q, %q): expected %d, got %d, %v", tc.a, tc.b, tc.expected, got, ok)
+		}
+	}
+
+	for _, v := range []string{"", "dev", "1.0.beta", "1..0", "latest-1.0"} {
+		if _, ok := compareVersions(v, "2.0.0"); ok {
+			t.Errorf("Expected %q not to be compared as a version", v)
+		}
+	}
+}
+
+func TestRemovalVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		removed bool
+	}{
+		{"2.0.0", true},
+		{"v2.1", true},
+		{"1.9.9", false},
+		{"2.0.0-rc1", false},
+		{"dev", false},
+		{"", false},
+	}
+	for _, tc := range tests {
+		rootCmd := &Command{Use: "root", Version: tc.version, Run: emptyRun}
+		rootCmd.AddCommand(&Command{Use: "old", Deprecation: &Deprecation{RemovalVersion: "2.0.0"}, Run: emptyRun})
+		_, _, err := executeCommandSplitOutput(rootCmd, "old")
+		if removed := err != nil; removed != tc.removed {
+			t.Errorf("Version %q: expected removed: %v, got %v", tc.version, tc.removed, err)
+		}
+	}
+}
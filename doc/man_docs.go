@@ -45,6 +45,7 @@ func GenManTree(cmd *cobra.Command, header *GenManHeader, dir string) error {
 // GenManTreeFromOpts generates a man page for the command and all descendants.
 // The pages are written to the opts.Path directory.
 func GenManTreeFromOpts(cmd *cobra.Command, opts GenManTreeOptions) error {
+	_ = cmd.ResolveCommands()
 	header := opts.Header
 	if header == nil {
 		header = &GenManHeader{}
@@ -102,6 +103,7 @@ type GenManHeader struct {
 // GenMan will generate a man page for the given command and write it to
 // w. The header argument may be nil, however obviously w may not.
 func GenMan(cmd *cobra.Command, header *GenManHeader, w io.Writer) error {
+	_ = cmd.ResolveCommands()
 	if header == nil {
 		header = &GenManHeader{}
 	}
@@ -154,6 +156,9 @@ func manPreamble(buf *bytes.Buffer, header *GenManHeader, cmd *cobra.Command, da
 	buf.WriteString("# SYNOPSIS\n")
 	buf.WriteString(fmt.Sprintf("**%s**\n\n", cmd.UseLine()))
 	buf.WriteString("# DESCRIPTION\n")
+	if notice := cmd.DeprecationNotice(); len(notice) > 0 {
+		buf.WriteString("**" + notice + "**\n\n")
+	}
 	buf.WriteString(description + "\n\n")
 }
 
@@ -47,6 +47,16 @@ func TestGenManDoc(t *testing.T) {
 	checkStringContains(t, output, translate("Auto generated"))
 }
 
+func TestGenManDeprecatedCmd(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := GenMan(deprecatedCmd, &GenManHeader{Title: "Project", Section: "2"}, buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	checkStringContains(t, output, "Deprecated: Please use echo instead")
+}
+
 func TestGenManNoHiddenParents(t *testing.T) {
 	header := &GenManHeader{
 		Title:   "Project",
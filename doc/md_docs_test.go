@@ -28,6 +28,16 @@ func TestGenMdDoc(t *testing.T) {
 	checkStringContains(t, output, "Options inherited from parent commands")
 }
 
+func TestGenMdDeprecatedCmd(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := GenMarkdown(deprecatedCmd, buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	checkStringContains(t, output, "> **Deprecated: Please use echo instead**\n\n"+deprecatedCmd.Short)
+}
+
 func TestGenMdDocWithNoLongOrSynopsis(t *testing.T) {
 	// We generate on subcommand so we have both subcommands and parents.
 	buf := new(bytes.Buffer)
@@ -95,6 +105,45 @@ func TestGenMdTree(t *testing.T) {
 	}
 }
 
+func TestGenMdTreeRegisteredCommands(t *testing.T) {
+	c := &cobra.Command{Use: "do", Run: emptyRun}
+	c.RegisterCommandFactory("", func() *cobra.Command {
+		return &cobra.Command{Use: "plugin", Short: "A registered command", Run: emptyRun}
+	})
+	tmpdir, err := ioutil.TempDir("", "test-gen-md-tree")
+	if err != nil {
+		t.Fatalf("Failed to create tmpdir: %v", err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	if err := GenMarkdownTree(c, tmpdir); err != nil {
+		t.Fatalf("GenMarkdownTree failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpdir, "do_plugin.md")); err != nil {
+		t.Fatalf("Expected file 'do_plugin.md' to exist")
+	}
+	content, err := ioutil.ReadFile(filepath.Join(tmpdir, "do.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkStringContains(t, string(content), "[do plugin](do_plugin.md)\t - A registered command")
+
+	// The commands which can't be added are left out with a warning
+	stderr := new(bytes.Buffer)
+	c.SetErr(stderr)
+	c.RegisterCommandFactory("missing", func() *cobra.Command {
+		return &cobra.Command{Use: "other", Run: emptyRun}
+	})
+	if err := GenMarkdownTree(c, tmpdir); err != nil {
+		t.Fatalf("GenMarkdownTree failed: %v", err)
+	}
+	expected := "Warning: parent command \"missing\" of a registered command not found in \"do\"\n"
+	if stderr.String() != expected {
+		t.Errorf("Expected the warning %q, got %q", expected, stderr.String())
+	}
+}
+
 func BenchmarkGenMarkdownToFile(b *testing.B) {
 	file, err := ioutil.TempFile("", "")
 	if err != nil {
@@ -59,6 +59,7 @@ func GenReST(cmd *cobra.Command, w io.Writer) error {
 
 // GenReSTCustom creates custom reStructured Text output.
 func GenReSTCustom(cmd *cobra.Command, w io.Writer, linkHandler func(string, string) string) error {
+	_ = cmd.ResolveCommands()
 	cmd.InitDefaultHelpCmd()
 	cmd.InitDefaultHelpFlag()
 
@@ -75,6 +76,9 @@ func GenReSTCustom(cmd *cobra.Command, w io.Writer, linkHandler func(string, str
 	buf.WriteString(".. _" + ref + ":\n\n")
 	buf.WriteString(name + "\n")
 	buf.WriteString(strings.Repeat("-", len(name)) + "\n\n")
+	if notice := cmd.DeprecationNotice(); len(notice) > 0 {
+		buf.WriteString(".. warning::\n\n   " + notice + "\n\n")
+	}
 	buf.WriteString(short + "\n\n")
 	buf.WriteString("Synopsis\n")
 	buf.WriteString("~~~~~~~~\n\n")
@@ -142,6 +146,7 @@ func GenReSTTree(cmd *cobra.Command, dir string) error {
 // GenReSTTreeCustom is the the same as GenReSTTree, but
 // with custom filePrepender and linkHandler.
 func GenReSTTreeCustom(cmd *cobra.Command, dir string, filePrepender func(string) string, linkHandler func(string, string) string) error {
+	_ = cmd.ResolveCommands()
 	for _, c := range cmd.Commands() {
 		if !c.IsAvailableCommand() || c.IsAdditionalHelpTopicCommand() {
 			continue
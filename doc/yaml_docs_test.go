@@ -26,6 +26,16 @@ func TestGenYamlDoc(t *testing.T) {
 	checkStringContains(t, output, echoSubCmd.Short)
 }
 
+func TestGenYamlDeprecatedCmd(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := GenYaml(deprecatedCmd, buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	checkStringContains(t, output, "deprecated: 'Deprecated: Please use echo instead'")
+}
+
 func TestGenYamlNoTag(t *testing.T) {
 	rootCmd.DisableAutoGenTag = true
 	defer func() { rootCmd.DisableAutoGenTag = false }()
@@ -30,11 +30,17 @@ end
 function __%[1]s_perform_completion
     __%[1]s_debug "Starting __%[1]s_perform_completion with: $argv"
 
-    set args (string split -- " " "$argv")
+    set args (string split -- " " "$argv[1]")
     set lastArg "$args[-1]"
 
+    # The words following the cursor, except the rest of the word being completed,
+    # are also sent, along with the index of the word being completed counted from the end
+    set afterArgs (string split --no-empty -- " " (string replace --regex -- '^\S*' '' "$argv[2]"))
+    set cursorIndex (math -1 - (count $afterArgs))
+
     __%[1]s_debug "args: $args"
     __%[1]s_debug "last arg: $lastArg"
+    __%[1]s_debug "after args: $afterArgs"
 
     set emptyArg ""
     if test -z "$lastArg"
@@ -49,7 +55,7 @@ function __%[1]s_perform_completion
         return
     end
 
-    set requestComp "$args[1] %[3]s $args[2..-1] $emptyArg"
+    set requestComp "$args[1] %[3]s %[10]s=$cursorIndex $args[2..-1] $emptyArg $afterArgs"
     __%[1]s_debug "Calling $requestComp"
 
     set results (eval $requestComp 2> /dev/null)
@@ -65,7 +71,12 @@ function __%[1]s_perform_completion
     __%[1]s_debug "flagPrefix: $flagPrefix"
 
     for comp in $comps
-        printf "%%s%%s\n" "$flagPrefix" "$comp"
+        if string match -q -- "%[9]s*" "$comp"
+            # Active help hints must not be prefixed
+            printf "%%s\n" "$comp"
+        else
+            printf "%%s%%s\n" "$flagPrefix" "$comp"
+        end
     end
 
     printf "%%s\n" "$directiveLine"
@@ -85,11 +96,15 @@ function __%[1]s_prepare_completions
     if not set --query __%[1]s_comp_commandLine
         # Use the -c flag to allow for completion in the middle of the line
         set __%[1]s_comp_commandLine (commandline -c)
+        # The text following the cursor is used for the flags it contains
+        set __%[1]s_comp_afterCursor (string sub --start (math (commandline -C) + 1) -- (commandline))
     end
     __%[1]s_debug "commandLine is: $__%[1]s_comp_commandLine"
+    __%[1]s_debug "afterCursor is: $__%[1]s_comp_afterCursor"
 
-    set results (__%[1]s_perform_completion "$__%[1]s_comp_commandLine")
+    set results (__%[1]s_perform_completion "$__%[1]s_comp_commandLine" "$__%[1]s_comp_afterCursor")
     set --erase __%[1]s_comp_commandLine
+    set --erase __%[1]s_comp_afterCursor
     __%[1]s_debug "Completion results: $results"
 
     if test -z "$results"
@@ -100,7 +115,23 @@ function __%[1]s_prepare_completions
     end
 
     set directive (string sub --start 2 $results[-1])
-    set --global __%[1]s_comp_results $results[1..-2]
+    set --global __%[1]s_comp_results
+
+    # Print the active help hints below the command-line instead of using them as completions
+    set activeHelp
+    for comp in $results[1..-2]
+        if string match -q -- "%[9]s*" "$comp"
+            set --append activeHelp (string sub --start (math (string length -- "%[9]s") + 1) -- "$comp")
+        else
+            set --append __%[1]s_comp_results $comp
+        end
+    end
+    if test -n "$activeHelp"
+        __%[1]s_debug "Active help: $activeHelp"
+        printf "\n%%s" $activeHelp >&2
+        printf "\n" >&2
+        commandline -f repaint
+    end
 
     __%[1]s_debug "Completions are: $__%[1]s_comp_results"
     __%[1]s_debug "Directive is: $directive"
@@ -183,7 +214,7 @@ complete -c %[2]s -n '__%[1]s_prepare_completions' -f -a '$__%[1]s_comp_results'
 
 `, nameForVar, name, compCmd,
 		ShellCompDirectiveError, ShellCompDirectiveNoSpace, ShellCompDirectiveNoFileComp,
-		ShellCompDirectiveFilterFileExt, ShellCompDirectiveFilterDirs))
+		ShellCompDirectiveFilterFileExt, ShellCompDirectiveFilterDirs, activeHelpMarker, ShellCompCursorArg))
 }
 
 // GenFishCompletion generates fish completion file and writes to the passed writer.
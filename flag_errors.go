@@ -0,0 +1,86 @@
+package cobra
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// UnknownFlagError is the error returned by ParseFlags() for an unknown flag.
+// It can be inspected by a FlagErrorFunc to handle unknown flags specially.
+type UnknownFlagError struct {
+	// Flag is the unknown flag as given on the command-line, with its
+	// leading dashes and without value, such as "--namspace" or "-x".
+	Flag string
+	// Suggestions are the flags of the command which may have been meant,
+	// such as "--namespace".  It is empty if suggestions are disabled.
+	Suggestions []string
+
+	err error
+	cmd *Command
+}
+
+// Error returns the error of the flag parser followed by the suggestions.
+func (e *UnknownFlagError) Error() string {
+	if len(e.Suggestions) == 0 {
+		return e.err.Error()
+	}
+	return fmt.Sprintf("%v\n\n%s", e.err, e.cmd.Translate("Did you mean %s?", strings.Join(e.Suggestions, e.cmd.Translate(" or "))))
+}
+
+// Unwrap returns the error of the flag parser.
+func (e *UnknownFlagError) Unwrap() error {
+	return e.err
+}
+
+// unknownShorthandRx matches the pflag errors for unknown shorthand flags,
+// with the quoted shorthand and the group of shorthands it is part of.
+var unknownShorthandRx = regexp.MustCompile(`^unknown shorthand flag: ('.+') in -(.*)$`)
+
+// unknownFlagError returns an UnknownFlagError for err if it is the error of
+// the flag parser for an unknown flag, and err unchanged otherwise.
+// A group of shorthands with an unknown one, such as "-namspace", is also
+// compared with the long flag names.
+func (c *Command) unknownFlagError(err error) error {
+	var typed []string
+	msg := err.Error()
+	if strings.HasPrefix(msg, "unknown flag: --") {
+		typed = []string{strings.TrimPrefix(msg, "unknown flag: ")}
+	} else if m := unknownShorthandRx.FindStringSubmatch(msg); m != nil {
+		shorthand, uerr := strconv.Unquote(m[1])
+		if uerr != nil {
+			return err
+		}
+		typed = []string{"-" + shorthand}
+		if group := strings.SplitN(m[2], "=", 2)[0]; group != shorthand {
+			typed = append(typed, "--"+group)
+		}
+	} else {
+		return err
+	}
+
+	e := &UnknownFlagError{Flag: typed[0], err: err, cmd: c}
+	if c.suggestionsDisabled() {
+		return e
+	}
+	for _, t := range typed {
+		for _, s := range c.FlagSuggestionsFor(t) {
+			if !stringInSlice(s, e.Suggestions) {
+				e.Suggestions = append(e.Suggestions, s)
+			}
+		}
+	}
+	return e
+}
+
+// suggestionsDisabled returns true if DisableSuggestions is set on the
+// command or on one of its parents.
+func (c *Command) suggestionsDisabled() bool {
+	for p := c; p != nil; p = p.parent {
+		if p.DisableSuggestions {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package cobra
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// FlagValuesAnnotation is the flag annotation holding the static list of
+// values accepted by a flag.  It is set by MarkFlagValues().
+const FlagValuesAnnotation = "cobra_annotation_flag_values"
+
+// FlagNoDefaultCompletionAnnotation is the flag annotation disabling the
+// completion of a flag based on its type.  It is set by
+// MarkFlagNoDefaultCompletion().
+const FlagNoDefaultCompletionAnnotation = "cobra_annotation_flag_no_default_completion"
+
+// enumValue is a string flag value restricted to a fixed list of values.
+type enumValue struct {
+	value  *string
+	values []string
+}
+
+// NewEnumValue returns a flag value which only accepts one of the specified values.
+// The value is stored in p and initialized to value.
+// Like for ValidArgs, each value can be followed by a tab character and a
+// description which will be shown by the shells supporting completion descriptions.
+// The values are used both to validate the flag when parsing the command-line
+// and to complete the flag in all shells.
+//
+// Example:
+//   cmd.Flags().VarP(cobra.NewEnumValue(&output, "json", "json\tJSON output", "yaml"), "output", "o", "output format")
+func NewEnumValue(p *string, value string, values ...string) pflag.Value {
+	*p = value
+	return &enumValue{value: p, values: values}
+}
+
+func (e *enumValue) Set(s string) error {
+	if err := checkFlagValue(s, e.values); err != nil {
+		return err
+	}
+	*e.value = s
+	return nil
+}
+
+func (e *enumValue) String() string { return *e.value }
+
+func (e *enumValue) Type() string { return "string" }
+
+// flagValues returns the static list of values accepted by flag, if any.
+// The values may include descriptions following a tab character.
+func flagValues(flag *pflag.Flag) []string {
+	if values, present := flag.Annotations[FlagValuesAnnotation]; present {
+		return values
+	}
+	if enum, ok := flag.Value.(*enumValue); ok {
+		return enum.values
+	}
+	return nil
+}
+
+// checkFlagValue returns an error listing the valid values if s is not one of them.
+func checkFlagValue(s string, values []string) error {
+	var names []string
+	for _, v := range values {
+		// Remove any description that may be included following a tab character.
+		name := strings.Split(v, "\t")[0]
+		if name == s {
+			return nil
+		}
+		names = append(names, fmt.Sprintf("%q", name))
+	}
+	return fmt.Errorf("must be one of %s", strings.Join(names, ", "))
+}
+
+// validateFlagValues checks that the flags marked with MarkFlagValues()
+// which were set on the command-line were given one of their valid values.
+// Flags using NewEnumValue() are already validated by the flag parsing.
+func (c *Command) validateFlagValues() error {
+	var err error
+	c.Flags().VisitAll(func(flag *pflag.Flag) {
+		values, present := flag.Annotations[FlagValuesAnnotation]
+		if err != nil || !present || !flag.Changed {
+			return
+		}
+
+		given := []string{flag.Value.String()}
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			given = slice.GetSlice()
+		}
+		for _, v := range given {
+			if e := checkFlagValue(v, values); e != nil {
+				flagName := "--" + flag.Name
+				if flag.Shorthand != "" && flag.ShorthandDeprecated == "" {
+					flagName = fmt.Sprintf("-%s, --%s", flag.Shorthand, flag.Name)
+				}
+				err = fmt.Errorf("invalid argument %q for %q flag: %v", v, flagName, e)
+				return
+			}
+		}
+	})
+	return err
+}
@@ -0,0 +1,77 @@
+package cobra
+
+import (
+	"testing"
+)
+
+func TestEnumFlagValue(t *testing.T) {
+	var output string
+	c := &Command{Use: "c", Run: emptyRun}
+	c.Flags().VarP(NewEnumValue(&output, "json", "json\tJSON output", "yaml"), "output", "o", "output format")
+
+	if output != "json" {
+		t.Errorf("Expected default value %q, got %q", "json", output)
+	}
+
+	if _, err := executeCommand(c, "--output", "yaml"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output != "yaml" {
+		t.Errorf("Expected value %q, got %q", "yaml", output)
+	}
+
+	_, err := executeCommand(c, "-o", "xml")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	got := err.Error()
+	expected := `invalid argument "xml" for "-o, --output" flag: must be one of "json", "yaml"`
+	if got != expected {
+		t.Errorf("Expected: %q, got: %q", expected, got)
+	}
+}
+
+func TestMarkFlagValues(t *testing.T) {
+	c := &Command{Use: "c", Run: emptyRun}
+	c.Flags().String("color", "", "color mode")
+	c.MarkFlagValues("color", "auto", "always\tAlways use colors", "never")
+
+	if _, err := executeCommand(c, "--color=always"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	_, err := executeCommand(c, "--color=sometimes")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	got := err.Error()
+	expected := `invalid argument "sometimes" for "--color" flag: must be one of "auto", "always", "never"`
+	if got != expected {
+		t.Errorf("Expected: %q, got: %q", expected, got)
+	}
+}
+
+func TestMarkPersistentFlagValuesOnSlice(t *testing.T) {
+	parent := &Command{Use: "parent", Run: emptyRun}
+	child := &Command{Use: "child", Run: emptyRun}
+	parent.AddCommand(child)
+	parent.PersistentFlags().StringSlice("level", nil, "levels")
+	parent.MarkPersistentFlagValues("level", "debug", "info", "error")
+
+	if _, err := executeCommand(parent, "child", "--level", "debug,info"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	_, err := executeCommand(parent, "child", "--level", "info,trace")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	got := err.Error()
+	expected := `invalid argument "trace" for "--level" flag: must be one of "debug", "info", "error"`
+	if got != expected {
+		t.Errorf("Expected: %q, got: %q", expected, got)
+	}
+}
@@ -0,0 +1,189 @@
+package cobra
+
+import (
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	flag "github.com/spf13/pflag"
+)
+
+// HelpStyle holds the ANSI SGR parameters used to style the help output,
+// for example "1" for bold or "1;36" for bold cyan.  An empty parameter
+// leaves the corresponding element unstyled.
+type HelpStyle struct {
+	// Heading styles the section headings, such as "Usage:" or "Flags:".
+	Heading string
+	// Command styles the names of the commands in the command lists.
+	Command string
+	// Flag styles the flag names in the flag lists.
+	Flag string
+}
+
+// DefaultHelpStyle is a help style using bold headings, cyan command names
+// and yellow flag names.
+var DefaultHelpStyle = &HelpStyle{Heading: "1", Command: "36", Flag: "33"}
+
+// terminalSize returns the width of w and true if w is a terminal.
+// It can be replaced for testing.
+var terminalSize = func(w io.Writer) (int, bool) {
+	if f, ok := w.(*os.File); ok {
+		return terminalWidth(f.Fd())
+	}
+	return 0, false
+}
+
+// flagNamesRx matches the names of a flag at the start of a line
+// of the output of FlagUsages().
+var flagNamesRx = regexp.MustCompile(`(?m)^  (-[^-\s])?(, |    )(--[^\s\[=]+)`)
+
+// HelpWidth returns the width to which the help output is wrapped, or 0 if
+// it is not wrapped.  The width is the one set with SetHelpWidth(), or else
+// the value of the COLUMNS environment variable, or else the width of
+// OutOrStdout() if it is a terminal.
+func (c *Command) HelpWidth() int {
+	for p := c; p != nil; p = p.parent {
+		if p.helpWidth < 0 {
+			return 0
+		}
+		if p.helpWidth > 0 {
+			return p.helpWidth
+		}
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	if width, ok := terminalSize(c.OutOrStdout()); ok {
+		return width
+	}
+	return 0
+}
+
+// helpStyleInUse returns the help style set with SetHelpStyle(), or nil if
+// the help output must not be styled since OutOrStdout() is not a terminal
+// or NO_COLOR is set.
+func (c *Command) helpStyleInUse() *HelpStyle {
+	var style *HelpStyle
+	for p := c; p != nil && style == nil; p = p.parent {
+		style = p.helpStyle
+	}
+	if style == nil || os.Getenv("NO_COLOR") != "" {
+		return nil
+	}
+	if _, ok := terminalSize(c.OutOrStdout()); !ok {
+		return nil
+	}
+	return style
+}
+
+// HelpHeading returns the heading s, translated and styled if help styling
+// is in use.  It is used by the default templates.
+func (c *Command) HelpHeading(s string) string {
+	return c.styleHeading(c.Translate(s))
+}
+
+// styleHeading returns the heading s styled if help styling is in use.
+func (c *Command) styleHeading(s string) string {
+	if style := c.helpStyleInUse(); style != nil {
+		return applyStyle(style.Heading, s)
+	}
+	return s
+}
+
+// HelpEntry returns an indented line of a command list made of name,
+// padded to padding, followed by description.  The description is wrapped
+// to the help width, with its following lines aligned on its first one.
+// It is used by the default templates.
+func (c *Command) HelpEntry(name string, padding int, description string) string {
+	padded := rpad(name, padding)
+	column := 2 + utf8.RuneCountInString(padded) + 1
+	if style := c.helpStyleInUse(); style != nil {
+		padded = applyStyle(style.Command, name) + padded[len(name):]
+	}
+	return "  " + padded + " " + wrapText(description, c.HelpWidth(), column)
+}
+
+// HelpText returns s with its lines longer than the help width wrapped.
+// It is used by the default templates for the long description.
+func (c *Command) HelpText(s string) string {
+	return wrapText(s, c.HelpWidth(), 0)
+}
+
+// HelpFlagUsages returns the usage of the flags, wrapped to the help width
+// and styled if help styling is in use.
+// It is used by the default templates.
+func (c *Command) HelpFlagUsages(flags *flag.FlagSet) string {
+	usages := flags.FlagUsagesWrapped(c.HelpWidth())
+	if style := c.helpStyleInUse(); style != nil && style.Flag != "" {
+		usages = flagNamesRx.ReplaceAllStringFunc(usages, func(line string) string {
+			m := flagNamesRx.FindStringSubmatch(line)
+			short := m[1]
+			if short != "" {
+				short = applyStyle(style.Flag, short)
+			}
+			return "  " + short + m[2] + applyStyle(style.Flag, m[3])
+		})
+	}
+	return usages
+}
+
+// applyStyle surrounds s with the ANSI escape sequences for the SGR parameters.
+func applyStyle(sgr, s string) string {
+	if sgr == "" {
+		return s
+	}
+	return "\x1b[" + sgr + "m" + s + "\x1b[0m"
+}
+
+// minWrapWidth is the minimum number of columns left to the text for
+// wrapping it to be sensible.
+const minWrapWidth = 24
+
+// wrapText wraps the lines of text longer than width at word boundaries,
+// as if the text started at column.  The lines following the first one
+// are indented to column, and a wrapped line continues with the same
+// indentation as its start.  Lines which fit are left untouched.
+func wrapText(text string, width, column int) string {
+	if width-column < minWrapWidth {
+		return text
+	}
+
+	indentation := strings.Repeat(" ", column)
+	var b strings.Builder
+	for i, line := range strings.Split(text, "\n") {
+		if i > 0 {
+			b.WriteString("\n")
+			if line != "" {
+				b.WriteString(indentation)
+			}
+		}
+		if column+utf8.RuneCountInString(line) <= width {
+			b.WriteString(line)
+			continue
+		}
+
+		trimmed := strings.TrimLeft(line, " \t")
+		lead := line[:len(line)-len(trimmed)]
+		b.WriteString(lead)
+		indent := column + utf8.RuneCountInString(lead)
+		pos := indent
+		for j, word := range strings.Fields(trimmed) {
+			length := utf8.RuneCountInString(word)
+			if j > 0 {
+				if pos+1+length > width {
+					b.WriteString("\n" + indentation + lead)
+					pos = indent
+				} else {
+					b.WriteString(" ")
+					pos++
+				}
+			}
+			b.WriteString(word)
+			pos += length
+		}
+	}
+	return b.String()
+}
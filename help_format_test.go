@@ -0,0 +1,117 @@
+package cobra
+
+import (
+	"io"
+	"os"
+	"testing"
+)
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		text     string
+		width    int
+		column   int
+		expected string
+	}{
+		// No wrapping without a width
+		{"a long text which is not wrapped", 0, 0, "a long text which is not wrapped"},
+		// Wrapping at word boundaries
+		{"aaaa bbbb cccc dddd eeee ffff gggg", 24, 0, "aaaa bbbb cccc dddd eeee\nffff gggg"},
+		// Hanging indentation from the column
+		{"aaaa bbbb cccc dddd eeee ffff gggg", 34, 10, "aaaa bbbb cccc dddd eeee\n          ffff gggg"},
+		// Lines which fit are untouched and the indentation of a line is kept
+		{"short  line\n  aaaa bbbb cccc dddd eeee ffff", 24, 0, "short  line\n  aaaa bbbb cccc dddd\n  eeee ffff"},
+		// No wrapping without enough space
+		{"aaaa bbbb cccc dddd eeee ffff gggg", 30, 10, "aaaa bbbb cccc dddd eeee ffff gggg"},
+	}
+	for _, tc := range tests {
+		if got := wrapText(tc.text, tc.width, tc.column); got != tc.expected {
+			t.Errorf("%q with width %d at column %d: expected: %q, got: %q", tc.text, tc.width, tc.column, tc.expected, got)
+		}
+	}
+}
+
+func TestHelpWidth(t *testing.T) {
+	defer os.Setenv("COLUMNS", os.Getenv("COLUMNS"))
+
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	childCmd := &Command{Use: "child", Run: emptyRun}
+	rootCmd.AddCommand(childCmd)
+
+	os.Setenv("COLUMNS", "")
+	if w := childCmd.HelpWidth(); w != 0 {
+		t.Errorf("Expected no width when the output is not a terminal, got %d", w)
+	}
+
+	os.Setenv("COLUMNS", "100")
+	if w := childCmd.HelpWidth(); w != 100 {
+		t.Errorf("Expected width from COLUMNS, got %d", w)
+	}
+
+	rootCmd.SetHelpWidth(60)
+	if w := childCmd.HelpWidth(); w != 60 {
+		t.Errorf("Expected width inherited from the parent, got %d", w)
+	}
+
+	childCmd.SetHelpWidth(-1)
+	if w := childCmd.HelpWidth(); w != 0 {
+		t.Errorf("Expected wrapping to be disabled, got %d", w)
+	}
+}
+
+func TestHelpWrapping(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.AddCommand(&Command{
+		Use:   "child",
+		Short: "a short description which is long enough to be wrapped",
+		Run:   emptyRun,
+	})
+	rootCmd.Flags().String("name", "", "a flag usage which is also long enough to be wrapped")
+	rootCmd.SetHelpWidth(50)
+
+	output, err := executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	checkStringContains(t, output, "  child       a short description which is long\n              enough to be wrapped\n")
+	checkStringContains(t, output, "      --name string   a flag usage which is\n                      also long enough to be\n")
+}
+
+func TestHelpStyle(t *testing.T) {
+	defer func(f func(io.Writer) (int, bool)) { terminalSize = f }(terminalSize)
+	defer os.Setenv("NO_COLOR", os.Getenv("NO_COLOR"))
+	os.Setenv("NO_COLOR", "")
+
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.AddCommand(&Command{Use: "child", Short: "child command", Run: emptyRun})
+	rootCmd.Flags().StringP("name", "n", "", "name")
+	rootCmd.Flags().Bool("all", false, "all")
+	rootCmd.SetHelpStyle(DefaultHelpStyle)
+	rootCmd.SetHelpWidth(-1)
+
+	// The output of the tests is not a terminal
+	output, err := executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringOmits(t, output, "\x1b[")
+
+	terminalSize = func(io.Writer) (int, bool) { return 80, true }
+	output, err = executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "\x1b[1mUsage:\x1b[0m\n")
+	checkStringContains(t, output, "\x1b[1mAvailable Commands:\x1b[0m\n")
+	checkStringContains(t, output, "  \x1b[36mchild\x1b[0m       child command\n")
+	checkStringContains(t, output, "  \x1b[33m-n\x1b[0m, \x1b[33m--name\x1b[0m string")
+	checkStringContains(t, output, "      \x1b[33m--all\x1b[0m ")
+
+	os.Setenv("NO_COLOR", "1")
+	output, err = executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringOmits(t, output, "\x1b[")
+}
@@ -0,0 +1,69 @@
+package cobra
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// HelpPager displays the help text of cmd, e.g. through a pager program.
+// A custom HelpPager can be set with SetHelpPager() to test the paging of
+// the help.
+type HelpPager func(cmd *Command, text string) error
+
+// defaultPager is the pager run by ExecHelpPager when PAGER is not set.
+const defaultPager = "less"
+
+// SetHelpPager sets the pager used by the default help function to display
+// the help of the command and of its descendants.  The help is not paged
+// when the pager is nil, which is the default.
+//
+// Example:
+//   rootCmd.SetHelpPager(cobra.ExecHelpPager)
+func (c *Command) SetHelpPager(pager HelpPager) {
+	c.helpPager = pager
+}
+
+// helpPagerInUse returns the pager set with SetHelpPager() on the command or
+// on its closest parent, or nil if the help is not paged.
+func (c *Command) helpPagerInUse() HelpPager {
+	for p := c; p != nil; p = p.parent {
+		if p.helpPager != nil {
+			return p.helpPager
+		}
+	}
+	return nil
+}
+
+// ExecHelpPager is a HelpPager running the program given by the PAGER
+// environment variable, or "less" if PAGER is not set, with the help text as
+// input.  The help text is written directly to OutOrStdout() if it is not a
+// terminal, if PAGER is set to an empty string, or if the pager cannot be
+// run.  LESS is set to "FRX" if not set, so that less exits if the help fits
+// on one screen and keeps the help colors.
+func ExecHelpPager(cmd *Command, text string) error {
+	out := cmd.OutOrStdout()
+	pager, ok := os.LookupEnv("PAGER")
+	if !ok {
+		pager = defaultPager
+	}
+	args := strings.Fields(pager)
+	if _, isTerminal := terminalSize(out); !isTerminal || len(args) == 0 {
+		_, err := io.WriteString(out, text)
+		return err
+	}
+
+	pagerCmd := exec.Command(args[0], args[1:]...)
+	pagerCmd.Stdin = strings.NewReader(text)
+	pagerCmd.Stdout = out
+	pagerCmd.Stderr = cmd.ErrOrStderr()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		pagerCmd.Env = append(os.Environ(), "LESS=FRX")
+	}
+	if err := pagerCmd.Start(); err != nil {
+		_, err = io.WriteString(out, text)
+		return err
+	}
+	return pagerCmd.Wait()
+}
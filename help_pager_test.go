@@ -0,0 +1,74 @@
+package cobra
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestHelpPager(t *testing.T) {
+	rootCmd := &Command{Use: "root", Short: "the root", Run: emptyRun}
+	childCmd := &Command{Use: "child", Short: "the child", Run: emptyRun}
+	rootCmd.AddCommand(childCmd)
+
+	var paged string
+	rootCmd.SetHelpPager(func(cmd *Command, text string) error {
+		paged = text
+		return nil
+	})
+
+	output, err := executeCommand(rootCmd, "child", "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if output != "" {
+		t.Errorf("Expected the help to be given to the pager only, got %q", output)
+	}
+	checkStringContains(t, paged, "the child\n\nUsage:\n  root child [flags]\n")
+}
+
+func TestExecHelpPager(t *testing.T) {
+	defer func(f func(io.Writer) (int, bool)) { terminalSize = f }(terminalSize)
+	defer func(pager string, ok bool) {
+		if ok {
+			os.Setenv("PAGER", pager)
+		} else {
+			os.Unsetenv("PAGER")
+		}
+	}(os.LookupEnv("PAGER"))
+
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+
+	// Not a terminal
+	os.Setenv("PAGER", "false")
+	output, err := executeCommandWithPager(rootCmd, "help text\n")
+	if err != nil || output != "help text\n" {
+		t.Errorf("Expected the help text to be written directly, got %q, %v", output, err)
+	}
+
+	if _, err := exec.LookPath("tr"); err != nil {
+		t.Skip("tr not found")
+	}
+	terminalSize = func(io.Writer) (int, bool) { return 80, true }
+	os.Setenv("PAGER", "tr a-z A-Z")
+	output, err = executeCommandWithPager(rootCmd, "help text\n")
+	if err != nil || output != "HELP TEXT\n" {
+		t.Errorf("Expected the help text through the pager, got %q, %v", output, err)
+	}
+
+	os.Setenv("PAGER", "")
+	output, err = executeCommandWithPager(rootCmd, "help text\n")
+	if err != nil || output != "help text\n" {
+		t.Errorf("Expected the help text to be written directly, got %q, %v", output, err)
+	}
+}
+
+func executeCommandWithPager(cmd *Command, text string) (string, error) {
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	defer cmd.SetOut(nil)
+	err := ExecHelpPager(cmd, text)
+	return buf.String(), err
+}
@@ -0,0 +1,67 @@
+package cobra
+
+import (
+	"fmt"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+// SearchHelp returns the available commands and additional help topics of
+// the tree of c, c included, whose Short, Long, Example or local flag usages
+// contain term, ignoring case.  The commands are in the order of the help.
+func (c *Command) SearchHelp(term string) []*Command {
+	term = strings.ToLower(term)
+	var matches []*Command
+	var search func(*Command)
+	search = func(cmd *Command) {
+		if cmd.helpContains(term) {
+			matches = append(matches, cmd)
+		}
+		for _, sub := range cmd.Commands() {
+			if sub.IsAvailableCommand() || sub.IsAdditionalHelpTopicCommand() || sub == cmd.helpCommand {
+				search(sub)
+			}
+		}
+	}
+	search(c)
+	return matches
+}
+
+// helpContains returns true if the help of the command contains the lower
+// case term.
+func (c *Command) helpContains(term string) bool {
+	for _, s := range []string{c.Short, c.Long, c.Example} {
+		if strings.Contains(strings.ToLower(s), term) {
+			return true
+		}
+	}
+	found := false
+	c.LocalFlags().VisitAll(func(f *flag.Flag) {
+		if !f.Hidden && strings.Contains(strings.ToLower(f.Usage), term) {
+			found = true
+		}
+	})
+	return found
+}
+
+// printHelpSearch prints the commands of the tree of c whose help contains
+// term, listed like the subcommands in the usage, to the output of the help.
+func (c *Command) printHelpSearch(term string) {
+	out := c.OutOrStdout()
+	matches := c.SearchHelp(term)
+	if len(matches) == 0 {
+		fmt.Fprintln(out, c.Translate("No help topics match %q.", term))
+		return
+	}
+	padding := minCommandPathPadding
+	for _, cmd := range matches {
+		if l := len(cmd.CommandPath()); l > padding {
+			padding = l
+		}
+	}
+	fmt.Fprintln(out, c.styleHeading(c.Translate("Help topics matching %q:", term)))
+	for _, cmd := range matches {
+		fmt.Fprintln(out, c.HelpEntry(cmd.CommandPath(), padding, cmd.Short))
+	}
+}
@@ -0,0 +1,102 @@
+package cobra
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func newHelpSearchTestCmd() *Command {
+	rootCmd := &Command{Use: "root", Short: "the root", Run: emptyRun}
+	configCmd := &Command{Use: "config", Short: "Manage the configuration", Run: emptyRun}
+	setCmd := &Command{Use: "set", Short: "Set a value", Example: "root config set color blue", Run: emptyRun}
+	setCmd.Flags().String("file", "", "the configuration FILE to write")
+	configCmd.AddCommand(setCmd)
+	topicCmd := &Command{Use: "colors", Short: "Help topic", Long: "The Colors are red, green and blue."}
+	hiddenCmd := &Command{Use: "hidden", Short: "blue", Hidden: true, Run: emptyRun}
+	rootCmd.AddCommand(configCmd, topicCmd, hiddenCmd)
+	return rootCmd
+}
+
+func TestSearchHelp(t *testing.T) {
+	rootCmd := newHelpSearchTestCmd()
+
+	tests := []struct {
+		term     string
+		expected []string
+	}{
+		{"blue", []string{"colors", "set"}},
+		{"configuration", []string{"config", "set"}},
+		{"file", []string{"set"}},
+		{"nothing", nil},
+	}
+	for _, tc := range tests {
+		var names []string
+		for _, cmd := range rootCmd.SearchHelp(tc.term) {
+			names = append(names, cmd.Name())
+		}
+		if len(names) != len(tc.expected) {
+			t.Errorf("%q: expected %v, got %v", tc.term, tc.expected, names)
+			continue
+		}
+		for i := range names {
+			if names[i] != tc.expected[i] {
+				t.Errorf("%q: expected %v, got %v", tc.term, tc.expected, names)
+				break
+			}
+		}
+	}
+}
+
+func TestHelpSearchCommand(t *testing.T) {
+	rootCmd := newHelpSearchTestCmd()
+
+	output, err := executeCommand(rootCmd, "help", "--search", "BLUE")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	expected := "Help topics matching \"BLUE\":\n  root colors     Help topic\n  root config set Set a value\n"
+	if output != expected {
+		t.Errorf("Expected:\n %q\nGot:\n %q\n", expected, output)
+	}
+
+	// Restricted to a command
+	output, err = executeCommand(rootCmd, "help", "config", "--search", "configuration")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "  root config     Manage the configuration\n")
+	checkStringOmits(t, output, "colors")
+
+	output, err = executeCommand(rootCmd, "help", "--search", "nothing")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if output != "No help topics match \"nothing\".\n" {
+		t.Errorf("Unexpected output: %q", output)
+	}
+
+	// Written to the standard output like the rest of the help, when no
+	// output is set
+	stdout, err := ioutil.TempFile("", "cobra-help-search")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(stdout.Name())
+	defer stdout.Close()
+	savedStdout := os.Stdout
+	os.Stdout = stdout
+	rootCmd.SetOut(nil)
+	rootCmd.SetErr(nil)
+	rootCmd.SetArgs([]string{"help", "--search", "blue"})
+	err = rootCmd.Execute()
+	os.Stdout = savedStdout
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	content, err := ioutil.ReadFile(stdout.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkStringContains(t, string(content), "Help topics matching \"blue\":\n")
+}
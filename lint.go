@@ -0,0 +1,321 @@
+package cobra
+
+import (
+	"fmt"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+// The checks of Lint, naming the kind of each Issue.
+const (
+	// LintMissingShort reports an available command without Short description.
+	LintMissingShort = "missing-short"
+	// LintDuplicateAlias reports a name or alias used by several sibling
+	// commands.
+	LintDuplicateAlias = "duplicate-alias"
+	// LintShadowedFlag reports a flag with the name of a persistent flag of a
+	// parent command, which silently takes precedence over it.
+	LintShadowedFlag = "shadowed-flag"
+	// LintShorthandConflict reports a flag whose shorthand is used by another
+	// flag of the command, including the inherited persistent flags.
+	LintShorthandConflict = "shorthand-conflict"
+	// LintValidArgsConflict reports a command with both ValidArgs and
+	// ValidArgsFunction.
+	LintValidArgsConflict = "valid-args-conflict"
+	// LintUseArgsMismatch reports a command whose Use line doesn't match the
+	// number of arguments accepted by its Args.
+	LintUseArgsMismatch = "use-args-mismatch"
+	// LintHiddenRequiredFlag reports a required flag which is hidden.
+	LintHiddenRequiredFlag = "hidden-required-flag"
+	// LintRegisteredCommand reports a registered command which can't be added
+	// to its parent command, as described by ResolveCommands.
+	LintRegisteredCommand = "registered-command"
+)
+
+// Issue is a mistake found in a command tree by Lint.
+type Issue struct {
+	// Command is the command with the issue.
+	Command *Command
+	// Check is the check which found the issue, e.g. LintMissingShort.
+	Check string
+	// Message describes the issue.
+	Message string
+}
+
+// String returns the issue prefixed with the path of its command.
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s (%s)", i.Command.CommandPath(), i.Message, i.Check)
+}
+
+// lintArgsLimit is the highest number of arguments with which Lint checks
+// the Args of a command against its Use line.
+const lintArgsLimit = 8
+
+// Lint checks the command tree of c for common mistakes, and returns the
+// issues found, in the order of the commands of the tree:
+//
+//   - available commands without Short description;
+//   - sibling commands sharing a name or an alias;
+//   - flags shadowing a persistent flag of a parent command;
+//   - flags whose shorthand is used by another flag of the command;
+//   - commands with both ValidArgs and ValidArgsFunction;
+//   - Use lines not matching the number of arguments accepted by Args;
+//   - required flags which are hidden;
+//   - registered commands which can't be added to the tree.
+//
+// The registered commands are resolved first, as by ResolveCommands, their
+// issues being reported on their parent command before the others.  The hidden and deprecated
+// commands are checked as well, except for their missing Short description.
+func Lint(c *Command) []Issue {
+	var issues []Issue
+	_ = c.ResolveCommands()
+	for _, e := range c.Root().commandFactoryErrors {
+		if e.parent.isDescendantOf(c) {
+			issues = append(issues, Issue{Command: e.parent, Check: LintRegisteredCommand, Message: e.err.Error()})
+		}
+	}
+	lintCommand(c, &issues)
+	return issues
+}
+
+// isDescendantOf returns true if c is cmd or one of its descendants.
+func (c *Command) isDescendantOf(cmd *Command) bool {
+	for p := c; p != nil; p = p.parent {
+		if p == cmd {
+			return true
+		}
+	}
+	return false
+}
+
+// lintCommand checks c and its subcommands.
+func lintCommand(c *Command, issues *[]Issue) {
+	report := func(check, format string, a ...interface{}) {
+		*issues = append(*issues, Issue{Command: c, Check: check, Message: fmt.Sprintf(format, a...)})
+	}
+
+	if c.Short == "" && c.IsAvailableCommand() {
+		report(LintMissingShort, "the command has no Short description")
+	}
+	if len(c.ValidArgs) > 0 && c.ValidArgsFunction != nil {
+		report(LintValidArgsConflict, "the command has both ValidArgs and ValidArgsFunction")
+	}
+	if msg := lintUseArgs(c); msg != "" {
+		report(LintUseArgsMismatch, "%s", msg)
+	}
+	lintFlags(c, report)
+
+	names := make(map[string]*Command)
+	for _, sub := range c.Commands() {
+		for _, name := range append([]string{sub.Name()}, sub.Aliases...) {
+			if other, ok := names[name]; ok && other != sub {
+				report(LintDuplicateAlias, "%q is used by the subcommands %q and %q", name, other.Name(), sub.Name())
+			}
+			names[name] = sub
+		}
+	}
+	for _, sub := range c.Commands() {
+		lintCommand(sub, issues)
+	}
+}
+
+// lintFlags checks the flags defined by c against each other and against the
+// persistent flags inherited from its parents.
+func lintFlags(c *Command, report func(check, format string, a ...interface{})) {
+	// The inherited flags and their shorthands, the ones of the closest
+	// parents taking precedence
+	inherited := make(map[string]*flag.Flag)
+	shorthands := make(map[string]*flag.Flag)
+	for p := c.parent; p != nil; p = p.parent {
+		p.PersistentFlags().VisitAll(func(f *flag.Flag) {
+			if _, ok := inherited[f.Name]; ok {
+				return
+			}
+			inherited[f.Name] = f
+			if _, ok := shorthands[f.Shorthand]; !ok && f.Shorthand != "" {
+				shorthands[f.Shorthand] = f
+			}
+		})
+	}
+
+	// The flags of the command, leaving out the inherited ones merged into
+	// its flags by a previous execution
+	var own []*flag.Flag
+	seen := make(map[*flag.Flag]bool)
+	for _, fs := range []*flag.FlagSet{c.PersistentFlags(), c.Flags()} {
+		fs.VisitAll(func(f *flag.Flag) {
+			if !seen[f] && inherited[f.Name] != f {
+				seen[f] = true
+				own = append(own, f)
+			}
+		})
+	}
+
+	for _, f := range own {
+		if parent, ok := inherited[f.Name]; ok {
+			report(LintShadowedFlag, "the flag --%s shadows the persistent flag --%s of a parent command", f.Name, parent.Name)
+		}
+		if f.Shorthand != "" && f.ShorthandDeprecated == "" {
+			if other, ok := shorthands[f.Shorthand]; ok && other.Name != f.Name {
+				report(LintShorthandConflict, "the shorthand -%s of the flag --%s is already used by the flag --%s", f.Shorthand, f.Name, other.Name)
+			}
+			shorthands[f.Shorthand] = f
+		}
+		if _, required := f.Annotations[BashCompOneRequiredFlag]; required && f.Hidden {
+			report(LintHiddenRequiredFlag, "the flag --%s is required but hidden", f.Name)
+		}
+	}
+}
+
+// lintUseArgs returns a message if the number of arguments given by the Use
+// line of c doesn't match the numbers of arguments accepted by its Args, or
+// blank string.  Commands without Args aren't checked.
+func lintUseArgs(c *Command) (msg string) {
+	if c.Args == nil {
+		return ""
+	}
+	min, max := useArgsCounts(c.Use)
+
+	arg := "arg"
+	if len(c.ValidArgs) > 0 {
+		arg = strings.Split(c.ValidArgs[0], "\t")[0]
+	}
+	accepted := make([]bool, lintArgsLimit+1)
+	defer func() {
+		// A custom Args may not expect to be called outside of an execution
+		if recover() != nil {
+			msg = ""
+		}
+	}()
+	for n := range accepted {
+		args := make([]string, n)
+		for i := range args {
+			args[i] = arg
+		}
+		accepted[n] = c.Args(c, args) == nil
+	}
+
+	for n := range accepted {
+		if accepted[n] != (n >= min && (max < 0 || n <= max)) {
+			return fmt.Sprintf("the use line %q expects %s argument(s) but Args accepts %s",
+				c.Use, describeArgsRange(min, max), describeArgsCounts(accepted))
+		}
+	}
+	return ""
+}
+
+// useArgsCounts returns the minimum and maximum numbers of arguments given by
+// the placeholders of a Use line, the maximum being -1 for any number.  The
+// placeholders are required, e.g. "<name>" or "NAME", or optional between
+// brackets, e.g. "[name]", and are repeated with "...".  The "[flags]" and
+// "[command]" placeholders and the flags are left out.
+func useArgsCounts(use string) (min, max int) {
+	var tokens []string
+	depth, start := 0, -1
+	for i, r := range use {
+		switch {
+		case r == '[':
+			if depth == 0 && start < 0 {
+				start = i
+			}
+			depth++
+		case r == ']':
+			if depth > 0 {
+				depth--
+			}
+		case r == ' ' && depth == 0:
+			if start >= 0 {
+				tokens = append(tokens, use[start:i])
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, use[start:])
+	}
+
+	if len(tokens) > 0 {
+		// The name of the command
+		tokens = tokens[1:]
+	}
+	for _, token := range tokens {
+		optional := strings.HasPrefix(token, "[")
+		inner := strings.Trim(token, "[]<>.")
+		if inner == "" || inner == "flags" || inner == "command" || strings.HasPrefix(inner, "-") {
+			continue
+		}
+		if strings.Contains(token, "...") {
+			max = -1
+		} else if max >= 0 {
+			max++
+		}
+		if !optional {
+			min++
+		}
+	}
+	return min, max
+}
+
+// describeArgsRange describes the numbers of arguments from min to max, max
+// being -1 for any number.
+func describeArgsRange(min, max int) string {
+	switch {
+	case max < 0:
+		return fmt.Sprintf("at least %d", min)
+	case min == max:
+		return fmt.Sprintf("%d", min)
+	}
+	return fmt.Sprintf("%d to %d", min, max)
+}
+
+// describeArgsCounts describes the numbers of arguments accepted, up to
+// lintArgsLimit.
+func describeArgsCounts(accepted []bool) string {
+	var counts []int
+	for n, ok := range accepted {
+		if ok {
+			counts = append(counts, n)
+		}
+	}
+	switch {
+	case len(counts) == 0:
+		return "none"
+	case counts[len(counts)-1] == len(accepted)-1 && counts[len(counts)-1]-counts[0] == len(counts)-1:
+		return describeArgsRange(counts[0], -1)
+	case counts[len(counts)-1]-counts[0] == len(counts)-1:
+		return describeArgsRange(counts[0], counts[len(counts)-1])
+	}
+	var s []string
+	for _, n := range counts {
+		s = append(s, fmt.Sprint(n))
+	}
+	return strings.Join(s, ", ")
+}
+
+// LintT is the part of testing.TB used by CheckLint.
+type LintT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// CheckLint reports each issue found by Lint in the command tree of c as an
+// error of t, and returns whether there were none.  It lets the tests of a
+// program check its commands.
+//
+// Example:
+//   func TestCommands(t *testing.T) {
+//     cobra.CheckLint(t, rootCmd)
+//   }
+func CheckLint(t LintT, c *Command) bool {
+	t.Helper()
+	issues := Lint(c)
+	for _, issue := range issues {
+		t.Errorf("%s", issue)
+	}
+	return len(issues) == 0
+}
@@ -0,0 +1,149 @@
+package cobra
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// lintTree returns a command tree with an issue for each check of Lint.
+func lintTree() *Command {
+	rootCmd := &Command{Use: "root", Short: "The root", Run: emptyRun}
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "")
+
+	userCmd := &Command{Use: "user", Aliases: []string{"u"}, Short: "Users", Run: emptyRun}
+	userCmd.Flags().Bool("verbose", false, "")
+	userCmd.Flags().StringP("version", "v", "", "")
+
+	groupCmd := &Command{Use: "group", Aliases: []string{"u"}, Run: emptyRun}
+	groupCmd.Flags().String("token", "", "")
+	groupCmd.MarkFlagRequired("token")
+	groupCmd.Flags().MarkHidden("token")
+
+	createCmd := &Command{
+		Use:       "create <name> [flags]",
+		Short:     "Create",
+		Args:      MaximumNArgs(2),
+		ValidArgs: []string{"a"},
+		ValidArgsFunction: func(*Command, []string, string) ([]string, ShellCompDirective) {
+			return nil, ShellCompDirectiveDefault
+		},
+		Run: emptyRun,
+	}
+	userCmd.AddCommand(createCmd)
+	rootCmd.AddCommand(userCmd, groupCmd)
+	return rootCmd
+}
+
+func TestLint(t *testing.T) {
+	var got []string
+	for _, issue := range Lint(lintTree()) {
+		got = append(got, issue.String())
+	}
+	expected := []string{
+		`root: "u" is used by the subcommands "group" and "user" (duplicate-alias)`,
+		`root group: the command has no Short description (missing-short)`,
+		`root group: the flag --token is required but hidden (hidden-required-flag)`,
+		`root user: the flag --verbose shadows the persistent flag --verbose of a parent command (shadowed-flag)`,
+		`root user: the shorthand -v of the flag --version is already used by the flag --verbose (shorthand-conflict)`,
+		`root user create: the command has both ValidArgs and ValidArgsFunction (valid-args-conflict)`,
+		`root user create: the use line "create <name> [flags]" expects 1 argument(s) but Args accepts 0 to 2 (use-args-mismatch)`,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected issues:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestLintClean(t *testing.T) {
+	rootCmd := &Command{Use: "root", Short: "The root", Args: NoArgs, Run: emptyRun}
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "")
+	testCases := []*Command{
+		{Use: "create <name> [alias]", Args: RangeArgs(1, 2)},
+		{Use: "add FILE...", Args: MinimumNArgs(1)},
+		{Use: "rm [file]... [flags]", Args: ArbitraryArgs},
+		{Use: "set [--force] <key> <value>", Args: ExactArgs(2)},
+		{Use: "pick <a|b>", Args: ExactValidArgs(1), ValidArgs: []string{"a\tthe first", "b"}},
+		{Use: "edit <name>", Args: func(*Command, []string) error { panic("not executed") }},
+		{Use: "secret", Hidden: true},
+	}
+	for i, cmd := range testCases {
+		if cmd.Short == "" && !cmd.Hidden {
+			cmd.Short = fmt.Sprintf("Command %d", i)
+		}
+		cmd.Run = emptyRun
+		rootCmd.AddCommand(cmd)
+	}
+
+	// The persistent flags merged by an execution aren't reported
+	if _, err := executeCommand(rootCmd, "create", "bob", "-v"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if issues := Lint(rootCmd); len(issues) > 0 {
+		t.Errorf("Expected no issues, got %v", issues)
+	}
+}
+
+func TestLintInheritedShorthands(t *testing.T) {
+	rootCmd := &Command{Use: "root", Short: "The root"}
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "")
+	midCmd := &Command{Use: "mid", Short: "The middle"}
+	midCmd.PersistentFlags().BoolP("vendor", "v", false, "")
+	leafCmd := &Command{Use: "leaf", Short: "The leaf", Run: emptyRun}
+	leafCmd.Flags().BoolP("value", "v", false, "")
+	midCmd.AddCommand(leafCmd)
+	rootCmd.AddCommand(midCmd)
+
+	// Reported against the flag of the closest parent
+	expected := "root mid leaf: the shorthand -v of the flag --value is already used by the flag --vendor (shorthand-conflict)"
+	for i := 0; i < 10; i++ {
+		issues := Lint(rootCmd)
+		if len(issues) != 2 || issues[1].String() != expected {
+			t.Fatalf("Expected the issue %q, got %v", expected, issues)
+		}
+	}
+}
+
+func TestUseArgsCounts(t *testing.T) {
+	testCases := []struct {
+		use      string
+		min, max int
+	}{
+		{"cmd", 0, 0},
+		{"cmd [flags]", 0, 0},
+		{"cmd [command]", 0, 0},
+		{"cmd <a> <b>", 2, 2},
+		{"cmd NAME [ALIAS]", 1, 2},
+		{"cmd [a [b]]", 0, 1},
+		{"cmd <file>...", 1, -1},
+		{"cmd [file ...]", 0, -1},
+		{"cmd -f <file>", 1, 1},
+	}
+	for _, tc := range testCases {
+		if min, max := useArgsCounts(tc.use); min != tc.min || max != tc.max {
+			t.Errorf("%q: expected %d to %d, got %d to %d", tc.use, tc.min, tc.max, min, max)
+		}
+	}
+}
+
+type lintT struct {
+	errors []string
+}
+
+func (t *lintT) Helper() {}
+
+func (t *lintT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestCheckLint(t *testing.T) {
+	lt := new(lintT)
+	if CheckLint(lt, lintTree()) || len(lt.errors) != 7 {
+		t.Errorf("Expected 7 errors, got %v", lt.errors)
+	}
+
+	lt = new(lintT)
+	if !CheckLint(lt, &Command{Use: "root", Short: "The root"}) || len(lt.errors) > 0 {
+		t.Errorf("Expected no errors, got %v", lt.errors)
+	}
+}
@@ -0,0 +1,89 @@
+package cobra
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// MessageCatalog provides the translations of the messages of cobra, such as
+// the headings of the default templates or the error messages.  A message is
+// identified by its English text, which is a format for fmt.Sprintf when the
+// message has arguments, e.g. "unknown command %q for %q".  A translation
+// must keep the verbs of the format in the same order.
+type MessageCatalog interface {
+	// Translate returns the translation of message for locale, and false
+	// if it has none.
+	Translate(locale, message string) (string, bool)
+}
+
+// MapMessageCatalog is a MessageCatalog holding the translations by locale,
+// then by English message.
+//
+// Example:
+//   cobra.MapMessageCatalog{
+//     "fr": {"Usage:": "Utilisation :", "Flags:": "Options :"},
+//   }
+type MapMessageCatalog map[string]map[string]string
+
+// Translate returns the translation of message for locale.
+func (m MapMessageCatalog) Translate(locale, message string) (string, bool) {
+	translation, ok := m[locale][message]
+	return translation, ok
+}
+
+// SetMessageCatalog sets the catalog of translations of the messages of the
+// command tree.  It must be called on the root command.
+func (c *Command) SetMessageCatalog(catalog MessageCatalog) {
+	c.messageCatalog = catalog
+}
+
+// SetLocale sets the locale of the messages of the command tree, e.g. "fr" or
+// "pt_BR", instead of the one of the environment.  It must be called on the
+// root command.
+func (c *Command) SetLocale(locale string) {
+	c.locale = locale
+}
+
+// Locale returns the locale of the messages of the command tree: the locale
+// set with SetLocale(), or else the one given by the LC_ALL, LC_MESSAGES or
+// LANG environment variables.  The encoding and modifier of the locale are
+// removed, e.g. "de_DE.UTF-8@euro" gives "de_DE".  It returns an empty
+// string for the "C" and "POSIX" locales.
+func (c *Command) Locale() string {
+	locale := c.Root().locale
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale != "" {
+			break
+		}
+		locale = os.Getenv(env)
+	}
+	if i := strings.IndexAny(locale, ".@"); i >= 0 {
+		locale = locale[:i]
+	}
+	if locale == "C" || locale == "POSIX" {
+		return ""
+	}
+	return strings.Replace(locale, "-", "_", -1)
+}
+
+// Translate returns the translation of message for the locale of the command
+// tree, formatted with args if there are any.  A message without translation
+// for the locale, e.g. "pt_BR", is looked up for its language, e.g. "pt", and
+// is used untranslated if it has no translation either, or if c is nil.
+func (c *Command) Translate(message string, args ...interface{}) string {
+	if c != nil && c.Root().messageCatalog != nil {
+		catalog, locale := c.Root().messageCatalog, c.Locale()
+		translation, ok := catalog.Translate(locale, message)
+		if i := strings.Index(locale, "_"); !ok && i > 0 {
+			translation, ok = catalog.Translate(locale[:i], message)
+		}
+		if ok {
+			message = translation
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
@@ -0,0 +1,132 @@
+package cobra
+
+import (
+	"os"
+	"testing"
+)
+
+var testCatalog = MapMessageCatalog{
+	"fr": {
+		"Usage:":              "Utilisation :",
+		"Available Commands:": "Commandes disponibles :",
+		"Flags:":              "Options :",
+		"Use \"%s [command] --help\" for more information about a command.": "Utilisez \"%s [commande] --help\" pour plus d'informations sur une commande.",
+		"help for %s":                   "aide pour %s",
+		"Help about any command":        "Aide sur n'importe quelle commande",
+		"Error:":                        "Erreur :",
+		"Run '%v --help' for usage.":    "Lancez '%v --help' pour l'utilisation.",
+		"unknown command %q for %q":     "commande %[2]q inconnue : %[1]q",
+		"Did you mean this?":            "Vouliez-vous dire ceci ?",
+		`required flag(s) "%s" not set`: `option(s) obligatoire(s) "%s" non définie(s)`,
+	},
+	"fr_CA": {
+		"Flags:": "Drapeaux :",
+	},
+}
+
+func TestLocale(t *testing.T) {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		defer os.Setenv(env, os.Getenv(env))
+		os.Setenv(env, "")
+	}
+
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	childCmd := &Command{Use: "child", Run: emptyRun}
+	rootCmd.AddCommand(childCmd)
+
+	tests := []struct {
+		lcAll, lang, explicit, expected string
+	}{
+		{"", "", "", ""},
+		{"", "C", "", ""},
+		{"", "de_DE.UTF-8@euro", "", "de_DE"},
+		{"fr_FR.UTF-8", "de_DE.UTF-8", "", "fr_FR"},
+		{"fr_FR.UTF-8", "de_DE.UTF-8", "pt-BR", "pt_BR"},
+	}
+	for _, tc := range tests {
+		os.Setenv("LC_ALL", tc.lcAll)
+		os.Setenv("LANG", tc.lang)
+		rootCmd.SetLocale(tc.explicit)
+		if got := childCmd.Locale(); got != tc.expected {
+			t.Errorf("LC_ALL=%q LANG=%q SetLocale(%q): expected %q, got %q", tc.lcAll, tc.lang, tc.explicit, tc.expected, got)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	childCmd := &Command{Use: "child", Run: emptyRun}
+	rootCmd.AddCommand(childCmd)
+
+	if got := childCmd.Translate("Flags:"); got != "Flags:" {
+		t.Errorf("Expected the message untranslated without catalog, got %q", got)
+	}
+
+	rootCmd.SetMessageCatalog(testCatalog)
+	tests := []struct {
+		locale, message, expected string
+	}{
+		{"fr", "Flags:", "Options :"},
+		{"fr_FR", "Flags:", "Options :"},
+		{"fr_CA", "Flags:", "Drapeaux :"},
+		{"fr_CA", "Usage:", "Utilisation :"},
+		{"de", "Flags:", "Flags:"},
+		{"fr", "Examples:", "Examples:"},
+	}
+	for _, tc := range tests {
+		rootCmd.SetLocale(tc.locale)
+		if got := childCmd.Translate(tc.message); got != tc.expected {
+			t.Errorf("%q in %q: expected %q, got %q", tc.message, tc.locale, tc.expected, got)
+		}
+	}
+
+	rootCmd.SetLocale("fr")
+	expected := `commande "root" inconnue : "foo"`
+	if got := childCmd.Translate("unknown command %q for %q", "foo", "root"); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+
+	var nilCmd *Command
+	if got := nilCmd.Translate("accepts %d arg(s), received %d", 1, 2); got != "accepts 1 arg(s), received 2" {
+		t.Errorf("Expected the message untranslated for a nil command, got %q", got)
+	}
+}
+
+func TestTranslatedHelp(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.AddCommand(&Command{Use: "child", Short: "a child", Run: emptyRun})
+	rootCmd.SetMessageCatalog(testCatalog)
+	rootCmd.SetLocale("fr")
+
+	output, err := executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "Utilisation :\n  root [flags]\n")
+	checkStringContains(t, output, "Commandes disponibles :\n")
+	checkStringContains(t, output, "  help        Aide sur n'importe quelle commande\n")
+	checkStringContains(t, output, "Options :\n  -h, --help   aide pour root\n")
+	checkStringContains(t, output, `Utilisez "root [commande] --help" pour plus d'informations sur une commande.`)
+}
+
+func TestTranslatedErrors(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	childCmd := &Command{Use: "child", Run: emptyRun}
+	childCmd.Flags().String("name", "", "name")
+	_ = childCmd.MarkFlagRequired("name")
+	rootCmd.AddCommand(childCmd)
+	rootCmd.SetMessageCatalog(testCatalog)
+	rootCmd.SetLocale("fr")
+
+	output, _ := executeCommand(rootCmd, "chil")
+	expected := "Erreur : commande \"root\" inconnue : \"chil\"\n\nVouliez-vous dire ceci ?\n\tchild\n\nLancez 'root --help' pour l'utilisation.\n"
+	if output != expected {
+		t.Errorf("Expected:\n %q\nGot:\n %q\n", expected, output)
+	}
+
+	_, err := executeCommand(rootCmd, "child")
+	expected = `option(s) obligatoire(s) "name" non définie(s)`
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected: %q, got: %v", expected, err)
+	}
+}
@@ -0,0 +1,250 @@
+package cobra
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnablePlugins enables the plugins of the command tree: the executables
+// named after the path of a command and the name of a subcommand, such as
+// "app-deploy" for "app deploy", or "app-user-export" for "app user export",
+// in the given directories, or in the directories of $PATH if none are given.
+// It must be called on the root command.
+//
+// An unknown subcommand of the root command, or of a command with
+// subcommands, is dispatched to the plugin with the longest name made of the
+// following arguments, e.g. "app-foo-bar" and then "app-foo" for
+// "app foo bar".  The plugin is executed with the remaining arguments,
+// including the flags, with the standard streams and the environment of the
+// program.  The subcommands take precedence over the plugins.
+//
+// A plugin exiting with a non-zero status makes Execute() return an
+// *exec.ExitError, whose exit code is left to the program.
+//
+// The directories are scanned once per execution, the first time a plugin is
+// looked up or listed.
+//
+// Example:
+//   rootCmd.EnablePlugins()
+//   if err := rootCmd.Execute(); err != nil {
+//     if exitErr, ok := err.(*exec.ExitError); ok {
+//       os.Exit(exitErr.ExitCode())
+//     }
+//     os.Exit(1)
+//   }
+func (c *Command) EnablePlugins(dirs ...string) {
+	c.pluginsEnabled = true
+	c.pluginDirs = dirs
+	c.pluginFiles = nil
+}
+
+// Plugin is an external executable providing a subcommand, as listed in the
+// help.
+type Plugin struct {
+	// Name is the name of the subcommand, with spaces between the names of
+	// nested subcommands, e.g. "export" or "export csv".
+	Name string
+	// Path is the path of the executable.
+	Path string
+
+	padding int
+}
+
+// NamePadding returns the padding for the name of the plugin.
+func (p Plugin) NamePadding() int {
+	return p.padding
+}
+
+// Plugins returns the plugins providing subcommands to the command, sorted by
+// name.  The plugins shadowed by a subcommand are omitted, as are the ones
+// of a directory listed after another directory with the same executable.
+func (c *Command) Plugins() []Plugin {
+	if !c.acceptsPlugins() {
+		return nil
+	}
+	prefix := c.pluginPrefix()
+	paths := make(map[string]string)
+	for name, path := range c.pluginExecutables() {
+		if !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+			continue
+		}
+		name = strings.Replace(name[len(prefix):], "-", " ", -1)
+		if c.hasSubCommand(strings.Fields(name)[0]) {
+			continue
+		}
+		paths[name] = path
+	}
+
+	padding := minNamePadding
+	plugins := make([]Plugin, 0, len(paths))
+	for name, path := range paths {
+		plugins = append(plugins, Plugin{Name: name, Path: path})
+		if len(name) > padding {
+			padding = len(name)
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	for i := range plugins {
+		plugins[i].padding = padding
+	}
+	return plugins
+}
+
+// HasPlugins returns true if plugins provide subcommands to the command.
+func (c *Command) HasPlugins() bool {
+	return len(c.Plugins()) > 0
+}
+
+// IsPlugin returns true if the command runs a plugin.
+func (c *Command) IsPlugin() bool {
+	return c.pluginPath != ""
+}
+
+// acceptsPlugins returns true if the unknown subcommands of the command are
+// dispatched to the plugins.
+func (c *Command) acceptsPlugins() bool {
+	return c.Root().pluginsEnabled && !c.IsPlugin() && (!c.HasParent() || c.HasSubCommands())
+}
+
+// pluginPrefix returns the prefix of the executables of the plugins of the
+// command, e.g. "app-user-" for "app user".
+func (c *Command) pluginPrefix() string {
+	return strings.Replace(c.CommandPath(), " ", "-", -1) + "-"
+}
+
+// pluginSearchDirs returns the directories searched for plugins.
+func (c *Command) pluginSearchDirs() []string {
+	if len(c.pluginDirs) > 0 {
+		return c.pluginDirs
+	}
+	return filepath.SplitList(os.Getenv("PATH"))
+}
+
+// pluginExecutables returns the paths of the executables of the plugin search
+// directories by name, the ones of the first directories taking precedence.
+// The directories are scanned once, and rescanned by the next execution of
+// the root command.
+func (c *Command) pluginExecutables() map[string]string {
+	root := c.Root()
+	if root.pluginFiles != nil {
+		return root.pluginFiles
+	}
+	prefix := root.pluginPrefix()
+	root.pluginFiles = make(map[string]string)
+	for _, dir := range root.pluginSearchDirs() {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			path := filepath.Join(dir, file.Name())
+			if file.Mode()&os.ModeSymlink != 0 {
+				if file, err = os.Stat(path); err != nil {
+					continue
+				}
+			}
+			name, ok := executableName(file)
+			if !ok || !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			if _, ok := root.pluginFiles[name]; !ok {
+				root.pluginFiles[name] = path
+			}
+		}
+	}
+	return root.pluginFiles
+}
+
+// findPlugin returns the command running the plugin with the longest name
+// made of the first arguments of argsWOflags, with args without the names,
+// or nil if there is none.
+func (c *Command) findPlugin(args, argsWOflags []string) (*Command, []string) {
+	if !c.acceptsPlugins() {
+		return nil, args
+	}
+	var names []string
+	for _, arg := range argsWOflags {
+		if isFlagArg(arg) || strings.ContainsAny(arg, `/\`) || arg == "" {
+			break
+		}
+		names = append(names, arg)
+	}
+
+	for n := len(names); n > 0; n-- {
+		path, ok := c.pluginExecutables()[c.pluginPrefix()+strings.Join(names[:n], "-")]
+		if !ok {
+			continue
+		}
+		parent := c
+		for _, name := range names[:n-1] {
+			parent = &Command{Use: name, Hidden: true, parent: parent}
+		}
+		for _, name := range names[:n] {
+			args = argsMinusFirstX(args, name)
+		}
+		return newPluginCommand(parent, names[n-1], path), args
+	}
+	return nil, args
+}
+
+// newPluginCommand returns the subcommand name of parent running the plugin
+// at path.  It isn't added to the subcommands of parent.
+func newPluginCommand(parent *Command, name, path string) *Command {
+	return &Command{
+		Use:                name,
+		Short:              parent.Translate("plugin %s", path),
+		DisableFlagParsing: true,
+		SilenceErrors:      true,
+		SilenceUsage:       true,
+		RunE: func(cmd *Command, args []string) error {
+			plugin := exec.CommandContext(cmd.Context(), path, args...)
+			plugin.Stdin = cmd.InOrStdin()
+			plugin.Stdout = cmd.OutOrStdout()
+			plugin.Stderr = cmd.ErrOrStderr()
+			return plugin.Run()
+		},
+		parent:     parent,
+		pluginPath: path,
+	}
+}
+
+// pluginCompletions forwards the completion request of the arguments args,
+// toComplete and afterArgs, following the cursor, to the plugin run by c,
+// which is expected to be a Cobra program as well, and returns its
+// completions and directive.  The plugins failing to complete get the default
+// completion.
+func (c *Command) pluginCompletions(args []string, toComplete string, afterArgs []string) ([]string, ShellCompDirective) {
+	request := []string{ShellCompRequestCmd}
+	if len(afterArgs) > 0 {
+		request = append(request, ShellCompCursorArg+"="+strconv.Itoa(len(args)))
+	}
+	request = append(append(append(request, args...), toComplete), afterArgs...)
+	plugin := exec.Command(c.pluginPath, request...)
+	plugin.Stdin = c.InOrStdin()
+	plugin.Stderr = ioutil.Discard
+	output, err := plugin.Output()
+	if err != nil {
+		return []string{}, ShellCompDirectiveDefault
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if len(lines) == 0 || !strings.HasPrefix(lines[len(lines)-1], ":") {
+		return []string{}, ShellCompDirectiveDefault
+	}
+	directive, err := strconv.Atoi(lines[len(lines)-1][1:])
+	if err != nil {
+		return []string{}, ShellCompDirectiveDefault
+	}
+	return lines[:len(lines)-1], ShellCompDirective(directive)
+}
@@ -0,0 +1,13 @@
+// +build !windows
+
+package cobra
+
+import (
+	"os"
+)
+
+// executableName returns the name of the file as a command, and false if the
+// file isn't executable.
+func executableName(file os.FileInfo) (string, bool) {
+	return file.Name(), file.Mode().IsRegular() && file.Mode()&0111 != 0
+}
@@ -0,0 +1,232 @@
+package cobra
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// writePlugins writes the stub plugins, printing their name, arguments and
+// the TEST_PLUGIN environment variable, to a temporary directory.
+func writePlugins(t *testing.T, names ...string) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("The stub plugins are shell scripts")
+	}
+	dir, err := ioutil.TempDir("", "cobra-plugins")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		script := "#!/bin/sh\n" +
+			`if [ "$1" = "__complete" ]; then case "$2" in __cursor=*) echo "$*";; *) echo "one"; echo "two	the second";; esac; echo ":4"; exit 0; fi` + "\n" +
+			`if [ "$1" = "fail" ]; then echo "failed" >&2; exit 3; fi` + "\n" +
+			`echo "` + name + ` $* $TEST_PLUGIN"` + "\n"
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Not executable
+	if err := ioutil.WriteFile(filepath.Join(dir, "root-data"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func newPluginsTestCommand(dir string) *Command {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.PersistentFlags().Bool("verbose", false, "")
+	userCmd := &Command{Use: "user", Short: "Manage the users"}
+	userCmd.AddCommand(&Command{Use: "create", Run: emptyRun})
+	rootCmd.AddCommand(userCmd, &Command{Use: "status", Short: "Show the status", Run: emptyRun})
+	rootCmd.EnablePlugins(dir)
+	return rootCmd
+}
+
+func TestPlugins(t *testing.T) {
+	dir := writePlugins(t, "root-deploy", "root-deploy-prod", "root-user-export", "root-status")
+	defer os.RemoveAll(dir)
+	rootCmd := newPluginsTestCommand(dir)
+	os.Setenv("TEST_PLUGIN", "env")
+	defer os.Unsetenv("TEST_PLUGIN")
+
+	testCases := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"deploy", "app", "--force"}, "root-deploy app --force env\n"},
+		{[]string{"deploy", "prod", "app"}, "root-deploy-prod app env\n"},
+		{[]string{"--verbose", "deploy", "staging"}, "root-deploy --verbose staging env\n"},
+		{[]string{"user", "export", "--all"}, "root-user-export --all env\n"},
+	}
+	for _, tc := range testCases {
+		output, err := executeCommand(rootCmd, tc.args...)
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", tc.args, err)
+		}
+		if output != tc.expected {
+			t.Errorf("%v: expected output %q, got %q", tc.args, tc.expected, output)
+		}
+	}
+
+	// The subcommands take precedence over the plugins
+	if output, err := executeCommand(rootCmd, "status"); err != nil || output != "" {
+		t.Errorf("Expected the status command to run, got %q, %v", output, err)
+	}
+
+	// The unknown commands without plugins are still reported
+	_, err := executeCommand(rootCmd, "data")
+	checkStringContains(t, err.Error(), `unknown command "data" for "root"`)
+
+	cmd, _, err := rootCmd.Find([]string{"deploy", "prod"})
+	if err != nil || !cmd.IsPlugin() || cmd.CommandPath() != "root deploy prod" {
+		t.Errorf("Expected the plugin root deploy prod, got %v, %v", cmd, err)
+	}
+}
+
+func TestPluginsTraverseChildren(t *testing.T) {
+	dir := writePlugins(t, "root-deploy", "root-deploy-prod")
+	defer os.RemoveAll(dir)
+	rootCmd := newPluginsTestCommand(dir)
+	rootCmd.TraverseChildren = true
+
+	// The flags of the parents are parsed by the parents
+	output, err := executeCommand(rootCmd, "--verbose", "deploy", "prod", "app", "--force")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output != "root-deploy-prod app --force \n" {
+		t.Errorf("Expected the plugin to run, got %q", output)
+	}
+	if verbose, _ := rootCmd.PersistentFlags().GetBool("verbose"); !verbose {
+		t.Error("Expected the verbose flag of the root to be set")
+	}
+
+	// The completion is forwarded to the plugin
+	output, err = executeCommand(rootCmd, ShellCompNoDescRequestCmd, "deploy", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "one\ntwo\n:4\n")
+}
+
+func TestPluginExitError(t *testing.T) {
+	dir := writePlugins(t, "root-deploy")
+	defer os.RemoveAll(dir)
+	rootCmd := newPluginsTestCommand(dir)
+
+	output, err := executeCommand(rootCmd, "deploy", "fail")
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok || exitErr.ExitCode() != 3 {
+		t.Fatalf("Expected the exit status 3, got %v", err)
+	}
+	if output != "failed\n" {
+		t.Errorf("Expected only the output of the plugin, got %q", output)
+	}
+}
+
+func TestPluginsHelp(t *testing.T) {
+	dir := writePlugins(t, "root-deploy", "root-deploy-prod", "root-user-export", "root-status")
+	defer os.RemoveAll(dir)
+	rootCmd := newPluginsTestCommand(dir)
+
+	output, err := executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "Plugins:\n"+
+		"  deploy      "+filepath.Join(dir, "root-deploy")+"\n"+
+		"  deploy prod "+filepath.Join(dir, "root-deploy-prod")+"\n")
+	checkStringOmits(t, output, "root-status")
+	checkStringOmits(t, output, "root-data")
+
+	output, err = executeCommand(rootCmd, "user", "--help")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "Plugins:\n  export      "+filepath.Join(dir, "root-user-export")+"\n")
+}
+
+func TestPluginsScannedOncePerExecution(t *testing.T) {
+	dir := writePlugins(t, "root-deploy")
+	defer os.RemoveAll(dir)
+	rootCmd := newPluginsTestCommand(dir)
+
+	if plugins := rootCmd.Plugins(); len(plugins) != 1 {
+		t.Fatalf("Expected the deploy plugin, got %v", plugins)
+	}
+	// A symbolic link to a plugin is a plugin as well
+	if err := os.Symlink(filepath.Join(dir, "root-deploy"), filepath.Join(dir, "root-build")); err != nil {
+		t.Fatal(err)
+	}
+	if rootCmd.HasPlugins() && len(rootCmd.Plugins()) != 1 {
+		t.Errorf("Expected the scan of the plugins to be cached, got %v", rootCmd.Plugins())
+	}
+
+	output, err := executeCommand(rootCmd, "build", "app")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output != "root-deploy app \n" {
+		t.Errorf("Expected the plugin to run, got %q", output)
+	}
+	if plugins := rootCmd.Plugins(); len(plugins) != 2 {
+		t.Errorf("Expected the build and deploy plugins, got %v", plugins)
+	}
+}
+
+func TestPluginsCompletion(t *testing.T) {
+	dir := writePlugins(t, "root-deploy", "root-deploy-prod", "root-user-export", "root-build-image")
+	defer os.RemoveAll(dir)
+	rootCmd := newPluginsTestCommand(dir)
+
+	output, err := executeCommand(rootCmd, ShellCompRequestCmd, "d")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := strings.Join([]string{
+		"deploy\tplugin " + filepath.Join(dir, "root-deploy"),
+		":4",
+		"Completion ended with directive: ShellCompDirectiveNoFileComp", ""}, "\n")
+	if output != expected {
+		t.Errorf("Expected output %q, got %q", expected, output)
+	}
+
+	// The nested plugins without a plugin of their first word can't be
+	// completed further, and are left out
+	output, err = executeCommand(rootCmd, ShellCompRequestCmd, "b")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringOmits(t, output, "build")
+
+	// The completion is forwarded to the plugin
+	output, err = executeCommand(rootCmd, ShellCompNoDescRequestCmd, "deploy", "app", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected = strings.Join([]string{
+		"one",
+		"two",
+		":4",
+		"Completion ended with directive: ShellCompDirectiveNoFileComp", ""}, "\n")
+	if output != expected {
+		t.Errorf("Expected output %q, got %q", expected, output)
+	}
+
+	// The arguments following the cursor are forwarded as well
+	output, err = executeCommand(rootCmd, ShellCompNoDescRequestCmd, ShellCompCursorArg+"=3", "--verbose", "deploy", "app", "st", "--force")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected = strings.Join([]string{
+		ShellCompRequestCmd + " " + ShellCompCursorArg + "=2 --verbose app st --force",
+		":4",
+		"Completion ended with directive: ShellCompDirectiveNoFileComp", ""}, "\n")
+	if output != expected {
+		t.Errorf("Expected output %q, got %q", expected, output)
+	}
+}
@@ -0,0 +1,31 @@
+// +build windows
+
+package cobra
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// executableExtensions returns the extensions of the executable files, given
+// by the PATHEXT environment variable.
+func executableExtensions() []string {
+	pathext := os.Getenv("PATHEXT")
+	if pathext == "" {
+		pathext = ".com;.exe;.bat;.cmd"
+	}
+	return strings.Split(strings.ToLower(pathext), ";")
+}
+
+// executableName returns the name of the file as a command, without its
+// extension, and false if the file isn't executable.
+func executableName(file os.FileInfo) (string, bool) {
+	ext := strings.ToLower(filepath.Ext(file.Name()))
+	for _, executableExt := range executableExtensions() {
+		if ext != "" && ext == executableExt && file.Mode().IsRegular() {
+			return strings.TrimSuffix(file.Name(), filepath.Ext(file.Name())), true
+		}
+	}
+	return "", false
+}
@@ -18,11 +18,11 @@ import (
 
 var powerShellCompletionTemplate = `using namespace System.Management.Automation
 using namespace System.Management.Automation.Language
-Register-ArgumentCompleter -Native -CommandName '%s' -ScriptBlock {
+Register-ArgumentCompleter -Native -CommandName '%[1]s' -ScriptBlock {
     param($wordToComplete, $commandAst, $cursorPosition)
     $commandElements = $commandAst.CommandElements
     $command = @(
-        '%s'
+        '%[2]s'
         for ($i = 1; $i -lt $commandElements.Count; $i++) {
             $element = $commandElements[$i]
             if ($element -isnot [StringConstantExpressionAst] -or
@@ -33,8 +33,50 @@ Register-ArgumentCompleter -Native -CommandName '%s' -ScriptBlock {
             $element.Value
         }
     ) -join ';'
-    $completions = @(switch ($command) {%s
-    })
+    $flagValues = @{%[3]s
+    }
+    # When completing the value of a flag which has a static list of values,
+    # only those values are completed.
+    $flag = ''
+    if ($wordToComplete -like '-*=*') {
+        $flag = $wordToComplete.Substring(0, $wordToComplete.IndexOf('=') + 1)
+    } elseif ($commandElements.Count -gt 1) {
+        $index = $commandElements.Count - 1
+        if ($wordToComplete) {
+            $index--
+        }
+        $flag = $commandElements[$index].ToString()
+    }
+    if ($flagValues.ContainsKey("$command;$flag")) {
+        $completions = $flagValues["$command;$flag"]
+    } else {
+        $completions = @(switch ($command) {%[4]s
+        })
+    }
+    # Ask the program for its active help hints and print them below the command-line.
+    # The arguments are passed as they were typed, without evaluating them.
+    $requestArgs = @('%[5]s')
+    for ($i = 1; $i -lt $commandElements.Count; $i++) {
+        $element = $commandElements[$i]
+        if ($element -is [StringConstantExpressionAst]) {
+            $requestArgs += $element.Value
+        } else {
+            $requestArgs += $element.Extent.Text
+        }
+    }
+    if (-not $wordToComplete) {
+        # An empty argument is dropped by the legacy passing of native arguments
+        if ($PSVersionTable.PSVersion -lt [version]'7.3.0' -or $PSNativeCommandArgumentPassing -eq 'Legacy') {
+            $requestArgs += '""'
+        } else {
+            $requestArgs += ''
+        }
+    }
+    $activeHelp = @(& '%[1]s' @requestArgs 2>$null | Where-Object { $_.StartsWith('%[6]s') })
+    if ($activeHelp.Count -gt 0) {
+        Write-Host ''
+        $activeHelp | ForEach-Object { Write-Host $_.Substring(%[7]d) }
+    }
     $completions.Where{ $_.CompletionText -like "$wordToComplete*" } |
         Sort-Object -Property ListItemText
 }`
@@ -72,6 +114,50 @@ func generatePowerShellSubcommandCases(out io.Writer, cmd *Command, previousComm
 	}
 }
 
+func generatePowerShellFlagValues(out io.Writer, cmd *Command, previousCommandName string) {
+	var cmdName string
+	if previousCommandName == "" {
+		cmdName = cmd.Name()
+	} else {
+		cmdName = fmt.Sprintf("%s;%s", previousCommandName, cmd.Name())
+	}
+
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		values := flagValues(flag)
+		if nonCompletableFlag(flag) || len(values) == 0 {
+			return
+		}
+		names := []string{"--" + flag.Name}
+		if len(flag.Shorthand) > 0 {
+			names = append(names, "-"+flag.Shorthand)
+		}
+		for _, name := range names {
+			// The value can be given as a separate word or following an =
+			for _, prefix := range []string{"", name + "="} {
+				key := name
+				if prefix != "" {
+					key = prefix
+				}
+				fmt.Fprintf(out, "\n        '%s;%s' = @(", cmdName, key)
+				for _, value := range values {
+					parts := strings.SplitN(value, "\t", 2)
+					desc := ""
+					if len(parts) == 2 {
+						desc = escapeStringForPowerShell(parts[1])
+					}
+					comp := escapeStringForPowerShell(parts[0])
+					fmt.Fprintf(out, "\n            [CompletionResult]::new('%s%s', '%s', [CompletionResultType]::ParameterValue, '%s')", escapeStringForPowerShell(prefix), comp, comp, desc)
+				}
+				fmt.Fprint(out, "\n        )")
+			}
+		}
+	})
+
+	for _, subCmd := range cmd.Commands() {
+		generatePowerShellFlagValues(out, subCmd, cmdName)
+	}
+}
+
 func escapeStringForPowerShell(s string) string {
 	return strings.Replace(s, "'", "''", -1)
 }
@@ -80,9 +166,13 @@ func escapeStringForPowerShell(s string) string {
 func (c *Command) GenPowerShellCompletion(w io.Writer) error {
 	buf := new(bytes.Buffer)
 
+	var flagValues bytes.Buffer
+	generatePowerShellFlagValues(&flagValues, c, "")
+
 	var subCommandCases bytes.Buffer
 	generatePowerShellSubcommandCases(&subCommandCases, c, "")
-	fmt.Fprintf(buf, powerShellCompletionTemplate, c.Name(), c.Name(), subCommandCases.String())
+	fmt.Fprintf(buf, powerShellCompletionTemplate, c.Name(), c.Name(), flagValues.String(), subCommandCases.String(),
+		ShellCompNoDescRequestCmd, activeHelpMarker, len(activeHelpMarker))
 
 	_, err := buf.WriteTo(w)
 	return err
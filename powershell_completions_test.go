@@ -104,6 +104,23 @@ func TestPowerShellCompletion(t *testing.T) {
 				"[CompletionResult]::new('sub1', 'sub1', [CompletionResultType]::ParameterValue, 'short describes ''sub1''')",
 			},
 		},
+		{
+			name: "flag values",
+			root: func() *Command {
+				r := &Command{Use: "values"}
+				r.Flags().StringP("output", "o", "", "")
+				r.MarkFlagValues("output", "json\tJSON output", "yaml")
+				return r
+			}(),
+			expectedExpressions: []string{
+				"'values;--output' = @(",
+				"'values;-o' = @(",
+				"'values;--output=' = @(",
+				"[CompletionResult]::new('json', 'json', [CompletionResultType]::ParameterValue, 'JSON output')",
+				"[CompletionResult]::new('yaml', 'yaml', [CompletionResultType]::ParameterValue, '')",
+				"[CompletionResult]::new('--output=json', 'json', [CompletionResultType]::ParameterValue, 'JSON output')",
+			},
+		},
 	}
 
 	for _, tc := range tcs {
@@ -0,0 +1,145 @@
+package cobra
+
+import (
+	"sort"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+// AmbiguousPrefixError is the error returned when a prefix given on the
+// command-line matches several subcommands with prefix matching, or several
+// flags with flag abbreviations.
+type AmbiguousPrefixError struct {
+	// Prefix is the prefix as given on the command-line, with the leading
+	// dashes for a flag.
+	Prefix string
+	// Matches are the names and aliases of the subcommands, or the flags
+	// with their leading dashes, which start with the prefix.
+	Matches []string
+	// Command is the command whose subcommands or flags are matched.
+	Command *Command
+}
+
+// Error lists the matches of the ambiguous prefix.
+func (e *AmbiguousPrefixError) Error() string {
+	if strings.HasPrefix(e.Prefix, "-") {
+		return e.Command.Translate("ambiguous flag %q for %q, it could be: %s", e.Prefix, e.Command.CommandPath(), strings.Join(e.Matches, ", "))
+	}
+	return e.Command.Translate("ambiguous command %q for %q, it could be: %s", e.Prefix, e.Command.CommandPath(), strings.Join(e.Matches, ", "))
+}
+
+// prefixMatching returns true if the subcommands of the command can be
+// given by a prefix of their name or aliases, with EnablePrefixMatching or
+// with PrefixMatching set on the command or on one of its parents.
+func (c *Command) prefixMatching() bool {
+	if EnablePrefixMatching {
+		return true
+	}
+	for p := c; p != nil; p = p.parent {
+		if p.PrefixMatching {
+			return true
+		}
+	}
+	return false
+}
+
+// ambiguousCommandError returns the error for the prefix matching several
+// subcommands, listing their names and aliases starting with the prefix.
+func (c *Command) ambiguousCommandError(prefix string, matches []*Command) error {
+	e := &AmbiguousPrefixError{Prefix: prefix, Command: c}
+	for _, cmd := range matches {
+		for _, name := range append([]string{cmd.Name()}, cmd.Aliases...) {
+			if strings.HasPrefix(name, prefix) {
+				e.Matches = append(e.Matches, name)
+			}
+		}
+	}
+	sort.Strings(e.Matches)
+	return e
+}
+
+// flagAbbreviations returns true if FlagAbbreviations is set on the command
+// or on one of its parents.
+func (c *Command) flagAbbreviations() bool {
+	for p := c; p != nil; p = p.parent {
+		if p.FlagAbbreviations {
+			return true
+		}
+	}
+	return false
+}
+
+// flagAbbreviationMatches returns the names of the flags of the command
+// starting with name, or name alone if it is the name of a flag.
+func (c *Command) flagAbbreviationMatches(name string) []string {
+	if name == "" || c.Flags().Lookup(name) != nil {
+		return []string{name}
+	}
+	var matches []string
+	c.Flags().VisitAll(func(f *flag.Flag) {
+		if strings.HasPrefix(f.Name, name) && len(f.Deprecated) == 0 {
+			matches = append(matches, f.Name)
+		}
+	})
+	sort.Strings(matches)
+	return matches
+}
+
+// expandFlagName returns the name of the flag abbreviated by name, or name
+// if flag abbreviations are disabled or name is not a unique abbreviation.
+func (c *Command) expandFlagName(name string) string {
+	if !c.flagAbbreviations() {
+		return name
+	}
+	if matches := c.flagAbbreviationMatches(name); len(matches) == 1 {
+		return matches[0]
+	}
+	return name
+}
+
+// expandFlagAbbreviations returns args with the unique abbreviations of long
+// flags replaced by the flag names, or an AmbiguousPrefixError for an
+// abbreviation of several flags.  Unknown flags are left to the flag parser.
+func (c *Command) expandFlagAbbreviations(args []string) ([]string, error) {
+	if !c.flagAbbreviations() {
+		return args, nil
+	}
+	flags := c.Flags()
+	expanded := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		s := args[i]
+		switch {
+		case s == "--":
+			// "--" terminates the flags
+			return append(expanded, args[i:]...), nil
+		case strings.HasPrefix(s, "--"):
+			nameAndValue := strings.SplitN(s[2:], "=", 2)
+			matches := c.flagAbbreviationMatches(nameAndValue[0])
+			if len(matches) > 1 {
+				e := &AmbiguousPrefixError{Prefix: "--" + nameAndValue[0], Command: c}
+				for _, m := range matches {
+					e.Matches = append(e.Matches, "--"+m)
+				}
+				return nil, e
+			}
+			if len(matches) == 1 {
+				nameAndValue[0] = matches[0]
+				s = "--" + strings.Join(nameAndValue, "=")
+			}
+			expanded = append(expanded, s)
+			if len(nameAndValue) == 1 && flags.Lookup(nameAndValue[0]) != nil && !hasNoOptDefVal(nameAndValue[0], flags) && i+1 < len(args) {
+				// The value of the flag is the next argument
+				i++
+				expanded = append(expanded, args[i])
+			}
+		case strings.HasPrefix(s, "-") && !strings.Contains(s, "=") && len(s) == 2 && flags.ShorthandLookup(s[1:]) != nil && !shortHasNoOptDefVal(s[1:], flags) && i+1 < len(args):
+			// The value of the shorthand flag is the next argument
+			expanded = append(expanded, s, args[i+1])
+			i++
+		default:
+			expanded = append(expanded, s)
+		}
+	}
+	return expanded, nil
+}
@@ -82,3 +82,47 @@ func (c *Command) MarkPersistentFlagDirname(name string) error {
 func MarkFlagDirname(flags *pflag.FlagSet, name string) error {
 	return flags.SetAnnotation(name, BashCompSubdirsInDir, []string{})
 }
+
+// MarkFlagValues instructs the various shell completion implementations to
+// complete the named flag with the specified values, and causes your command
+// to report an error if the flag is given any other value.
+// Like for ValidArgs, each value can be followed by a tab character and a
+// description.
+func (c *Command) MarkFlagValues(name string, values ...string) error {
+	return MarkFlagValues(c.Flags(), name, values...)
+}
+
+// MarkPersistentFlagValues instructs the various shell completion
+// implementations to complete the named persistent flag with the specified
+// values, and causes your command to report an error if the flag is given
+// any other value.
+func (c *Command) MarkPersistentFlagValues(name string, values ...string) error {
+	return MarkFlagValues(c.PersistentFlags(), name, values...)
+}
+
+// MarkFlagValues instructs the various shell completion implementations to
+// complete the named flag with the specified values, and causes your command
+// to report an error if the flag is given any other value.
+func MarkFlagValues(flags *pflag.FlagSet, name string, values ...string) error {
+	return flags.SetAnnotation(name, FlagValuesAnnotation, values)
+}
+
+// MarkFlagNoDefaultCompletion instructs the various shell completion
+// implementations not to complete the named flag based on its type, e.g.,
+// with true and false for a bool flag or with units for a duration flag.
+func (c *Command) MarkFlagNoDefaultCompletion(name string) error {
+	return MarkFlagNoDefaultCompletion(c.Flags(), name)
+}
+
+// MarkPersistentFlagNoDefaultCompletion instructs the various shell
+// completion implementations not to complete the named persistent flag
+// based on its type.
+func (c *Command) MarkPersistentFlagNoDefaultCompletion(name string) error {
+	return MarkFlagNoDefaultCompletion(c.PersistentFlags(), name)
+}
+
+// MarkFlagNoDefaultCompletion instructs the various shell completion
+// implementations not to complete the named flag based on its type.
+func MarkFlagNoDefaultCompletion(flags *pflag.FlagSet, name string) error {
+	return flags.SetAnnotation(name, FlagNoDefaultCompletionAnnotation, []string{"true"})
+}
@@ -0,0 +1,203 @@
+package cobra
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// completionProgEnvVar makes the test binary behave as the program of
+// newScriptTestCmd(), so that the completion scripts can call it.
+const completionProgEnvVar = "COBRA_TEST_COMPLETION_PROG"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(completionProgEnvVar) == "1" {
+		rootCmd := newScriptTestCmd()
+		rootCmd.SetArgs(os.Args[1:])
+		if err := rootCmd.Execute(); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func newScriptTestCmd() *Command {
+	rootCmd := &Command{Use: "prog", Run: emptyRun}
+	getCmd := &Command{
+		Use:   "get",
+		Short: "Display resources",
+		ValidArgsFunction: func(cmd *Command, args []string, toComplete string) ([]string, ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, ShellCompDirectiveNoFileComp
+			}
+			resources := []string{"pods\tThe pods", "nodes\tThe nodes"}
+			if all, _ := cmd.Flags().GetBool("all"); all {
+				resources = append(resources, "secrets\tThe secrets")
+			}
+			var comps []string
+			for _, comp := range resources {
+				if strings.HasPrefix(comp, toComplete) {
+					comps = append(comps, comp)
+				}
+			}
+			return comps, ShellCompDirectiveNoFileComp
+		},
+		Run: emptyRun,
+	}
+	var output string
+	getCmd.Flags().VarP(NewEnumValue(&output, "json", "json", "yaml"), "output", "o", "output format")
+	getCmd.Flags().Bool("all", false, "include all resources")
+	getCmd.Flags().Duration("timeout", 0, "timeout of the request")
+	rootCmd.AddCommand(getCmd, &Command{Use: "delete", Short: "Delete resources", Run: emptyRun})
+	// Generate the help command in the scripts like it is added on execution
+	rootCmd.InitDefaultHelpCmd()
+	return rootCmd
+}
+
+// scriptTests are the command-lines completed by the shell scripts of
+// newScriptTestCmd(), with their expected completions in any order.
+// The cursor is at the end of line, followed by the words of after.
+var scriptTests = []struct {
+	line        string
+	after       string
+	completions []string
+}{
+	{"prog ", "", []string{"delete", "get", "help"}},
+	{"prog g", "", []string{"get"}},
+	{"prog get ", "", []string{"nodes", "pods"}},
+	{"prog get p", "", []string{"pods"}},
+	{"prog get --output ", "", []string{"json", "yaml"}},
+	{"prog get -o y", "", []string{"yaml"}},
+	{"prog get ", " --all", []string{"nodes", "pods", "secrets"}},
+	{"prog get s", " --all -o json", []string{"secrets"}},
+	{"prog get --output ", " pods", []string{"json", "yaml"}},
+	{"prog get --timeout 1", "", []string{"1h", "1m", "1ms", "1s"}},
+}
+
+// runCompletionScript runs the shell with the arguments, letting the
+// completion script call the test binary as the program.
+func runCompletionScript(t *testing.T, shell string, args ...string) string {
+	prog, err := os.Executable()
+	if err != nil {
+		t.Fatalf("Unable to find the test binary: %v", err)
+	}
+
+	cmd := exec.Command(shell, args...)
+	cmd.Env = append(os.Environ(), completionProgEnvVar+"=1", "COBRA_TEST_PROG="+prog)
+	stderr := new(bytes.Buffer)
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("%s failed: %v\n%s", shell, err, stderr.String())
+	}
+	return string(out)
+}
+
+// writeCompletionScript writes the completion script into a temporary
+// directory and returns its path.
+func writeCompletionScript(t *testing.T, name string, gen func(*bytes.Buffer) error) (string, func()) {
+	dir, err := ioutil.TempDir("", "cobra-completion")
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	if err := gen(buf); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path, func() { os.RemoveAll(dir) }
+}
+
+func checkScriptCompletions(t *testing.T, line, output string, expected []string) {
+	completions := strings.Fields(output)
+	sort.Strings(completions)
+	if !reflect.DeepEqual(completions, expected) {
+		t.Errorf("%q: expected: %q\ngot: %q", line, expected, completions)
+	}
+}
+
+// bashDriver completes the command-line given as second argument, followed
+// by the words given as third argument, using the bash completion script
+// given as first argument.  The functions of the bash-completion package
+// are replaced if it is not installed.
+const bashDriver = `
+source "$1"
+if ! declare -F _get_comp_words_by_ref >/dev/null; then
+    _get_comp_words_by_ref() {
+        cur=${COMP_WORDS[COMP_CWORD]}
+        prev=${COMP_WORDS[COMP_CWORD-1]}
+        words=("${COMP_WORDS[@]}")
+        cword=$COMP_CWORD
+    }
+fi
+prog() { "$COBRA_TEST_PROG" "$@"; }
+
+COMP_LINE=$2$3
+COMP_POINT=${#2}
+COMP_TYPE=9
+read -r -a COMP_WORDS <<< "$2"
+if [[ $2 == *" " ]]; then
+    COMP_WORDS+=("")
+fi
+COMP_CWORD=$((${#COMP_WORDS[@]}-1))
+read -r -a after <<< "$3"
+COMP_WORDS+=("${after[@]}")
+__start_prog
+printf "%s\n" "${COMPREPLY[@]}"
+`
+
+func TestBashCompletionScriptRun(t *testing.T) {
+	if err := exec.Command("which", "bash").Run(); err != nil {
+		t.Skip("bash is not available")
+	}
+
+	script, cleanup := writeCompletionScript(t, "prog.bash", func(buf *bytes.Buffer) error {
+		return newScriptTestCmd().GenBashCompletion(buf)
+	})
+	defer cleanup()
+
+	for _, tc := range scriptTests {
+		output := runCompletionScript(t, "bash", "-c", bashDriver, "bash", script, tc.line, tc.after)
+		checkScriptCompletions(t, tc.line+"<TAB>"+tc.after, output, tc.completions)
+	}
+}
+
+// fishDriver completes the command-line given as second argument, followed
+// by the words given as third argument, using the fish completion script
+// given as first argument.
+const fishDriver = `
+source $argv[1]
+function prog
+    $COBRA_TEST_PROG $argv
+end
+set __prog_comp_commandLine $argv[2]
+set __prog_comp_afterCursor $argv[3]
+__prog_prepare_completions
+printf "%s\n" $__prog_comp_results
+`
+
+func TestFishCompletionScriptRun(t *testing.T) {
+	if err := exec.Command("which", "fish").Run(); err != nil {
+		t.Skip("fish is not available")
+	}
+
+	script, cleanup := writeCompletionScript(t, "prog.fish", func(buf *bytes.Buffer) error {
+		return newScriptTestCmd().GenFishCompletion(buf, false)
+	})
+	defer cleanup()
+
+	for _, tc := range scriptTests {
+		output := runCompletionScript(t, "fish", "-c", fishDriver, script, tc.line, tc.after)
+		checkScriptCompletions(t, tc.line+"<TAB>"+tc.after, output, tc.completions)
+	}
+}
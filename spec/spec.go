@@ -0,0 +1,671 @@
+// Package spec builds cobra command trees at runtime from declarative YAML
+// or JSON descriptions.
+//
+// The description of a command uses the keys of the documentation generated
+// by doc.GenYaml, like the spec files of cobra generate, and binds the Run
+// function of the command by name to a handler of a Registry:
+//
+//   name: app
+//   commands:
+//     - name: greet
+//       usage: greet <name> [flags]
+//       synopsis: Greet someone
+//       args: exact:1
+//       run: greet
+//       options:
+//         - name: shout
+//           type: bool
+//           usage: greet loudly
+//
+// The spec is validated before building the commands, and each error is
+// located by the path of the invalid value in the spec, e.g.
+// "commands[0].options[1].type".
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+)
+
+// Command is the declarative description of a command and of its
+// subcommands.
+type Command struct {
+	// Name is the name of the command.
+	Name string `yaml:"name" json:"name"`
+	// Synopsis is the short description of the command.
+	Synopsis string `yaml:"synopsis" json:"synopsis"`
+	// Description is the long description of the command.
+	Description string `yaml:"description" json:"description"`
+	// Usage is the use line of the command, e.g. "greet <name> [flags]",
+	// optionally prefixed with the path of its parent command as generated by
+	// doc.GenYaml.  It defaults to the name.
+	Usage      string   `yaml:"usage" json:"usage"`
+	Example    string   `yaml:"example" json:"example"`
+	Deprecated string   `yaml:"deprecated" json:"deprecated"`
+	Hidden     bool     `yaml:"hidden" json:"hidden"`
+	Aliases    []string `yaml:"aliases" json:"aliases"`
+	// Version is the version of the command, printed by its version flag.
+	Version string `yaml:"version" json:"version"`
+	// Args is the name of the policy of the positional arguments, as
+	// accepted by ParsePolicy.
+	Args        string            `yaml:"args" json:"args"`
+	ValidArgs   []string          `yaml:"valid_args" json:"valid_args"`
+	Annotations map[string]string `yaml:"annotations" json:"annotations"`
+	// Run is the name of the handler of the Registry running the command.
+	// A command without handler only groups its subcommands.
+	Run      string     `yaml:"run" json:"run"`
+	Options  []Option   `yaml:"options" json:"options"`
+	Commands []*Command `yaml:"commands" json:"commands"`
+}
+
+// Option is the declarative description of a flag.
+type Option struct {
+	Name      string `yaml:"name" json:"name"`
+	Shorthand string `yaml:"shorthand" json:"shorthand"`
+	// DefaultValue is the default value of the flag as printed in the help,
+	// e.g. "8080", "5s" or "[a,b]".
+	DefaultValue string `yaml:"default_value" json:"default_value"`
+	Usage        string `yaml:"usage" json:"usage"`
+	// Type is the type of the flag: string, bool, int, int64, uint, float64,
+	// duration, stringSlice, stringArray or intSlice.  It is inferred from the
+	// default value if it is not set: bool for true or false, stringSlice for
+	// a list in brackets, int for an integer, duration for a duration and
+	// string otherwise.
+	Type       string `yaml:"type" json:"type"`
+	Persistent bool   `yaml:"persistent" json:"persistent"`
+	Required   bool   `yaml:"required" json:"required"`
+	Hidden     bool   `yaml:"hidden" json:"hidden"`
+}
+
+// Handler runs a command, like the RunE function of a cobra.Command.
+type Handler func(cmd *cobra.Command, args []string) error
+
+// Registry maps the names of the handlers of a spec to their functions.
+type Registry map[string]Handler
+
+// Error is an error of a spec, located by the path of the invalid value in
+// the spec, e.g. "commands[0].options[1].type".
+type Error struct {
+	// File is the file of the spec, if any.
+	File string
+	// Path is the location of the invalid value, empty for the spec itself.
+	Path string
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	var location []string
+	for _, s := range []string{e.File, e.Path} {
+		if s != "" {
+			location = append(location, s)
+		}
+	}
+	if len(location) == 0 {
+		return e.Msg
+	}
+	return strings.Join(location, ": ") + ": " + e.Msg
+}
+
+// Errors are the errors found validating a spec.
+type Errors []*Error
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Parse parses a spec in the JSON format if it starts with a brace, and in
+// the YAML format otherwise.  Unknown keys are reported as errors.  The
+// errors are returned as an *Error, or as Errors if there are several,
+// located by their line in the spec, e.g. "line 3" for YAML or
+// "line 3, column 5" for JSON.
+func Parse(data []byte) (*Command, error) {
+	spec := &Command{}
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(spec); err != nil {
+			return nil, &Error{Path: jsonErrorLocation(data, err), Msg: err.Error()}
+		}
+		return spec, nil
+	}
+	if err := yaml.UnmarshalStrict(data, spec); err != nil {
+		return nil, yamlError(err)
+	}
+	return spec, nil
+}
+
+// yamlLine matches the location of a YAML decoding error, e.g.
+// "yaml: line 3: did not find expected key" or "line 4: field x not found".
+var yamlLine = regexp.MustCompile(`^(?:yaml: )?(line \d+): (.*)$`)
+
+// yamlError returns the YAML decoding error err as an *Error, or as Errors
+// if there are several, located by their line if they have one.
+func yamlError(err error) error {
+	messages := []string{err.Error()}
+	if typeErr, ok := err.(*yaml.TypeError); ok {
+		messages = typeErr.Errors
+	}
+	var errs Errors
+	for _, msg := range messages {
+		e := &Error{Msg: msg}
+		if m := yamlLine.FindStringSubmatch(msg); m != nil {
+			e.Path, e.Msg = m[1], m[2]
+		}
+		errs = append(errs, e)
+	}
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return errs
+}
+
+// jsonErrorLocation returns the line and column of a JSON decoding error,
+// or blank string if it has no offset.
+func jsonErrorLocation(data []byte, err error) string {
+	var offset int64
+	switch err := err.(type) {
+	case *json.SyntaxError:
+		offset = err.Offset
+	case *json.UnmarshalTypeError:
+		offset = err.Offset
+	default:
+		return ""
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := int(offset) - bytes.LastIndex(before, []byte("\n"))
+	return fmt.Sprintf("line %d, column %d", line, column)
+}
+
+// ReadFile reads and parses the spec of the file at path.
+func ReadFile(path string) (*Command, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := Parse(data)
+	if err != nil {
+		return nil, setFile(err, path)
+	}
+	return spec, nil
+}
+
+// setFile sets the file of the errors of a spec to path, and returns err.
+func setFile(err error, path string) error {
+	switch err := err.(type) {
+	case *Error:
+		err.File = path
+	case Errors:
+		for _, e := range err {
+			e.File = path
+		}
+	}
+	return err
+}
+
+// Load builds the command tree of the spec of the file at path, with the
+// handlers of the registry.
+func Load(path string, handlers Registry) (*cobra.Command, error) {
+	spec, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cmd, err := spec.Build(handlers)
+	if err != nil {
+		return nil, setFile(err, path)
+	}
+	return cmd, nil
+}
+
+// Build validates the spec and builds its command tree, with the handlers of
+// the registry.  It returns all the errors of the spec as Errors.
+func (c *Command) Build(handlers Registry) (*cobra.Command, error) {
+	b := &builder{handlers: handlers}
+	cmd := b.command(c, "", "", map[string]string{}, false)
+	if len(b.errs) > 0 {
+		return nil, b.errs
+	}
+	return cmd, nil
+}
+
+// builder builds a command tree, collecting the errors of the spec.
+type builder struct {
+	handlers Registry
+	errs     Errors
+}
+
+// errorf records an error at the path of the spec joined with key.
+func (b *builder) errorf(path, key, format string, a ...interface{}) {
+	if key != "" {
+		if path != "" && !strings.HasPrefix(key, "[") {
+			path += "."
+		}
+		path += key
+	}
+	b.errs = append(b.errs, &Error{Path: path, Msg: fmt.Sprintf(format, a...)})
+}
+
+// command builds the command of spec located at path, whose parent command
+// has the given command path.  shorthands maps the shorthands of the
+// persistent flags inherited by the command to their names, and help is true
+// if one of them is a help flag.
+func (b *builder) command(spec *Command, path, parentPath string, shorthands map[string]string, help bool) *cobra.Command {
+	name := spec.Name
+	if name == "" || strings.ContainsAny(name, " \t\n") {
+		b.errorf(path, "name", "invalid command name %q", name)
+	}
+	commandPath := strings.TrimSpace(parentPath + " " + name)
+
+	cmd := &cobra.Command{
+		Use:         b.use(spec, path, parentPath),
+		Aliases:     spec.Aliases,
+		Short:       strings.TrimSpace(spec.Synopsis),
+		Long:        strings.TrimSpace(spec.Description),
+		Example:     strings.TrimRight(spec.Example, "\n"),
+		Deprecated:  spec.Deprecated,
+		Hidden:      spec.Hidden,
+		Version:     spec.Version,
+		ValidArgs:   spec.ValidArgs,
+		Annotations: spec.Annotations,
+	}
+
+	if spec.Args != "" {
+		policy, err := ParsePolicy(spec.Args)
+		if err != nil {
+			b.errorf(path, "args", "%v", err)
+		} else if len(spec.ValidArgs) == 0 && policy.NeedsValidArgs() {
+			b.errorf(path, "args", "args policy %q needs valid_args", spec.Args)
+		}
+		cmd.Args = policy.Args()
+	}
+
+	if spec.Run != "" {
+		handler, ok := b.handlers[spec.Run]
+		if !ok {
+			b.errorf(path, "run", "unknown run handler %q", spec.Run)
+		}
+		cmd.RunE = handler
+	}
+
+	inherited := make(map[string]string, len(shorthands))
+	for shorthand, name := range shorthands {
+		inherited[shorthand] = name
+	}
+	if other, ok := inherited["v"]; ok && spec.Version != "" && !hasOption(spec, "version") {
+		b.errorf(path, "version", "shorthand \"v\" of the version flag is already used by flag %q", other)
+	}
+	help = b.options(cmd, spec, path, inherited, help)
+
+	names := make(map[string]int)
+	for i, sub := range spec.Commands {
+		subPath := fmt.Sprintf("%s[%d]", joinPath(path, "commands"), i)
+		if sub == nil {
+			b.errorf(subPath, "", "empty command")
+			continue
+		}
+		for _, n := range append([]string{sub.Name}, sub.Aliases...) {
+			if j, ok := names[n]; ok && n != "" {
+				b.errorf(subPath, "", "name or alias %q of %q already used by commands[%d]", n, commandPath+" "+sub.Name, j)
+			}
+			names[n] = i
+		}
+		cmd.AddCommand(b.command(sub, subPath, commandPath, inherited, help))
+	}
+	return cmd
+}
+
+// joinPath joins a path of the spec and a key.
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// use returns the use line of the command: its usage without the path of the
+// parent command and the flags placeholder.
+func (b *builder) use(spec *Command, path, parentPath string) string {
+	use := strings.TrimSpace(spec.Usage)
+	use = strings.TrimSpace(strings.TrimSuffix(use, "[flags]"))
+	if parentPath != "" {
+		use = strings.TrimSpace(strings.TrimPrefix(use, parentPath+" "))
+	}
+	if use == "" {
+		return spec.Name
+	}
+	if strings.Fields(use)[0] != spec.Name {
+		b.errorf(path, "usage", "usage %q doesn't start with the name of the command %q", spec.Usage, spec.Name)
+	}
+	return use
+}
+
+// options defines the flags of the command.  shorthands maps the shorthands
+// of the flags visible to the command to their names, and is completed with
+// the persistent ones of the command.  help is true if a persistent help flag
+// is inherited; options returns true if one is visible to the subcommands.
+//
+// The shorthands of the default help flag, "h", and of the default version
+// flag, "v" when the command has a version, are reserved unless the command
+// defines these flags.
+func (b *builder) options(cmd *cobra.Command, spec *Command, path string, shorthands map[string]string, help bool) bool {
+	local := make(map[string]string)
+	if !help && !hasOption(spec, "help") {
+		local["h"] = "help"
+	}
+	if spec.Version != "" && !hasOption(spec, "version") {
+		local["v"] = "version"
+	}
+	seen := make(map[string]bool)
+	for i, o := range spec.Options {
+		optionPath := fmt.Sprintf("%s[%d]", joinPath(path, "options"), i)
+		if o.Name == "" || strings.ContainsAny(o.Name, " \t\n=") {
+			b.errorf(optionPath, "name", "invalid flag name %q", o.Name)
+			continue
+		}
+		if seen[o.Name] {
+			b.errorf(optionPath, "name", "flag %q is declared twice", o.Name)
+			continue
+		}
+		seen[o.Name] = true
+		if o.Shorthand != "" {
+			if len(o.Shorthand) != 1 {
+				b.errorf(optionPath, "shorthand", "shorthand %q of flag %q is more than one character", o.Shorthand, o.Name)
+				continue
+			}
+			for _, used := range []map[string]string{shorthands, local} {
+				if other, ok := used[o.Shorthand]; ok && other != o.Name {
+					b.errorf(optionPath, "shorthand", "shorthand %q of flag %q is already used by flag %q", o.Shorthand, o.Name, other)
+				}
+			}
+			local[o.Shorthand] = o.Name
+		}
+
+		flags := cmd.Flags()
+		if o.Persistent {
+			flags = cmd.PersistentFlags()
+		}
+		if err := defineFlag(flags, o); err != nil {
+			key := "default_value"
+			if _, ok := err.(unknownTypeError); ok {
+				key = "type"
+			}
+			b.errorf(optionPath, key, "%v", err)
+			continue
+		}
+		if o.Hidden {
+			flags.MarkHidden(o.Name)
+		}
+		if o.Required {
+			if o.Persistent {
+				cmd.MarkPersistentFlagRequired(o.Name)
+			} else {
+				cmd.MarkFlagRequired(o.Name)
+			}
+		}
+	}
+
+	for _, o := range spec.Options {
+		if o.Persistent && o.Shorthand != "" {
+			shorthands[o.Shorthand] = o.Name
+		}
+		if o.Persistent && o.Name == "help" {
+			help = true
+		}
+	}
+	return help
+}
+
+// hasOption returns true if the command declares the option name.
+func hasOption(spec *Command, name string) bool {
+	for _, o := range spec.Options {
+		if o.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// unknownTypeError reports an unknown flag type.
+type unknownTypeError string
+
+func (t unknownTypeError) Error() string {
+	return fmt.Sprintf("unknown flag type %q", string(t))
+}
+
+// defineFlag defines the flag of the option in flags.
+func defineFlag(flags *pflag.FlagSet, o Option) error {
+	def := strings.TrimSpace(o.DefaultValue)
+	typ := o.Type
+	if typ == "" {
+		typ = InferType(def)
+	}
+	invalid := func(err error) error {
+		return fmt.Errorf("invalid default value %q of %s flag %q: %v", def, typ, o.Name, err)
+	}
+
+	switch typ {
+	case "string":
+		flags.StringP(o.Name, o.Shorthand, def, o.Usage)
+	case "bool":
+		v, err := parseOr(def, false, func(s string) (interface{}, error) { return strconv.ParseBool(s) })
+		if err != nil {
+			return invalid(err)
+		}
+		flags.BoolP(o.Name, o.Shorthand, v.(bool), o.Usage)
+	case "int":
+		v, err := parseOr(def, 0, func(s string) (interface{}, error) { return strconv.Atoi(s) })
+		if err != nil {
+			return invalid(err)
+		}
+		flags.IntP(o.Name, o.Shorthand, v.(int), o.Usage)
+	case "int64":
+		v, err := parseOr(def, int64(0), func(s string) (interface{}, error) { return strconv.ParseInt(s, 10, 64) })
+		if err != nil {
+			return invalid(err)
+		}
+		flags.Int64P(o.Name, o.Shorthand, v.(int64), o.Usage)
+	case "uint":
+		v, err := parseOr(def, uint64(0), func(s string) (interface{}, error) { return strconv.ParseUint(s, 10, 0) })
+		if err != nil {
+			return invalid(err)
+		}
+		flags.UintP(o.Name, o.Shorthand, uint(v.(uint64)), o.Usage)
+	case "float64":
+		v, err := parseOr(def, float64(0), func(s string) (interface{}, error) { return strconv.ParseFloat(s, 64) })
+		if err != nil {
+			return invalid(err)
+		}
+		flags.Float64P(o.Name, o.Shorthand, v.(float64), o.Usage)
+	case "duration":
+		v, err := parseOr(def, time.Duration(0), func(s string) (interface{}, error) { return time.ParseDuration(s) })
+		if err != nil {
+			return invalid(err)
+		}
+		flags.DurationP(o.Name, o.Shorthand, v.(time.Duration), o.Usage)
+	case "stringSlice":
+		flags.StringSliceP(o.Name, o.Shorthand, SliceValues(def), o.Usage)
+	case "stringArray":
+		flags.StringArrayP(o.Name, o.Shorthand, SliceValues(def), o.Usage)
+	case "intSlice":
+		var values []int
+		for _, s := range SliceValues(def) {
+			v, err := strconv.Atoi(s)
+			if err != nil {
+				return invalid(err)
+			}
+			values = append(values, v)
+		}
+		flags.IntSliceP(o.Name, o.Shorthand, values, o.Usage)
+	default:
+		return unknownTypeError(typ)
+	}
+	return nil
+}
+
+// parseOr parses s with parse, or returns zero if s is empty.
+func parseOr(s string, zero interface{}, parse func(string) (interface{}, error)) (interface{}, error) {
+	if s == "" {
+		return zero, nil
+	}
+	return parse(s)
+}
+
+// SliceValues returns the values of the default value of a slice flag, e.g.
+// "a" and "b" for "[a,b]", or nil if it is empty.
+func SliceValues(s string) []string {
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+	if s == "" {
+		return nil
+	}
+	values := strings.Split(s, ",")
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+	return values
+}
+
+// InferType returns the type of a flag from its default value: bool for true
+// or false, stringSlice for a list in brackets, int for an integer, duration
+// for a duration and string otherwise.
+func InferType(value string) string {
+	value = strings.TrimSpace(value)
+	switch {
+	case value == "true" || value == "false":
+		return "bool"
+	case strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"):
+		return "stringSlice"
+	}
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return "int"
+	}
+	if _, err := time.ParseDuration(value); err == nil {
+		return "duration"
+	}
+	return "string"
+}
+
+// Policy is a parsed policy of the positional arguments.
+type Policy struct {
+	// Func is the name of the cobra validator of the policy, e.g. "RangeArgs".
+	Func string
+	// Counts are the numbers of arguments given to the validator, if any.
+	Counts []int
+}
+
+// policyFuncs maps the names of the policies to their validators and to
+// their numbers of counts.
+var policyFuncs = map[string]struct {
+	fn     string
+	counts int
+}{
+	"none":        {"NoArgs", 0},
+	"arbitrary":   {"ArbitraryArgs", 0},
+	"only-valid":  {"OnlyValidArgs", 0},
+	"exact":       {"ExactArgs", 1},
+	"exact-valid": {"ExactValidArgs", 1},
+	"min":         {"MinimumNArgs", 1},
+	"max":         {"MaximumNArgs", 1},
+	"range":       {"RangeArgs", 2},
+}
+
+// ParsePolicy parses the policy of the positional arguments named policy:
+//
+//   none            cobra.NoArgs
+//   arbitrary       cobra.ArbitraryArgs
+//   only-valid      cobra.OnlyValidArgs
+//   exact:N         cobra.ExactArgs(N)
+//   exact-valid:N   cobra.ExactValidArgs(N)
+//   min:N           cobra.MinimumNArgs(N)
+//   max:N           cobra.MaximumNArgs(N)
+//   range:MIN,MAX   cobra.RangeArgs(MIN, MAX)
+func ParsePolicy(policy string) (Policy, error) {
+	name, param := policy, ""
+	if i := strings.Index(policy, ":"); i >= 0 {
+		name, param = policy[:i], policy[i+1:]
+	}
+	f, ok := policyFuncs[name]
+	if !ok {
+		return Policy{}, fmt.Errorf("unknown args policy %q", policy)
+	}
+	if f.counts == 0 {
+		if param != "" {
+			return Policy{}, fmt.Errorf("args policy %q takes no parameter", name)
+		}
+		return Policy{Func: f.fn}, nil
+	}
+
+	values := strings.Split(param, ",")
+	if param == "" || len(values) != f.counts {
+		example := name + ":2"
+		if f.counts == 2 {
+			example = name + ":1,3"
+		}
+		return Policy{}, fmt.Errorf("args policy %q takes %d number(s) of arguments, e.g. %q", name, f.counts, example)
+	}
+	counts := make([]int, f.counts)
+	for i, v := range values {
+		var err error
+		counts[i], err = strconv.Atoi(strings.TrimSpace(v))
+		if err != nil || counts[i] < 0 {
+			return Policy{}, fmt.Errorf("invalid args policy %q: %q is not a number of arguments", policy, v)
+		}
+	}
+	if f.counts == 2 && counts[0] > counts[1] {
+		return Policy{}, fmt.Errorf("invalid args policy %q: the minimum is greater than the maximum", policy)
+	}
+	return Policy{Func: f.fn, Counts: counts}, nil
+}
+
+// NeedsValidArgs returns true if the validator of the policy checks the
+// arguments against the valid args of the command.
+func (p Policy) NeedsValidArgs() bool {
+	return p.Func == "OnlyValidArgs" || p.Func == "ExactValidArgs"
+}
+
+// Args returns the validator of the policy.
+func (p Policy) Args() cobra.PositionalArgs {
+	switch p.Func {
+	case "NoArgs":
+		return cobra.NoArgs
+	case "ArbitraryArgs":
+		return cobra.ArbitraryArgs
+	case "OnlyValidArgs":
+		return cobra.OnlyValidArgs
+	case "ExactArgs":
+		return cobra.ExactArgs(p.Counts[0])
+	case "ExactValidArgs":
+		return cobra.ExactValidArgs(p.Counts[0])
+	case "MinimumNArgs":
+		return cobra.MinimumNArgs(p.Counts[0])
+	case "MaximumNArgs":
+		return cobra.MaximumNArgs(p.Counts[0])
+	case "RangeArgs":
+		return cobra.RangeArgs(p.Counts[0], p.Counts[1])
+	}
+	return nil
+}
+
+// ArgsPolicy returns the positional arguments validator named policy, as
+// parsed by ParsePolicy.
+func ArgsPolicy(policy string) (cobra.PositionalArgs, error) {
+	p, err := ParsePolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+	return p.Args(), nil
+}
@@ -0,0 +1,373 @@
+package spec
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const testSpec = `
+name: app
+synopsis: An application
+options:
+  - name: verbose
+    shorthand: v
+    type: bool
+    persistent: true
+    usage: print the details
+commands:
+  - name: user
+    synopsis: Manage the users
+    annotations:
+      group: admin
+    commands:
+      - name: create
+        usage: app user create <name> [flags]
+        synopsis: Create a user
+        description: |
+          Create a user and add it to its team.
+        aliases: [new]
+        args: exact:1
+        run: create
+        options:
+          - name: team
+            shorthand: t
+            default_value: core
+            required: true
+            usage: team of the user
+          - name: quota
+            default_value: "10"
+          - name: expiry
+            default_value: 720h
+          - name: groups
+            default_value: "[dev,ops]"
+          - name: ratio
+            type: float64
+            default_value: "0.5"
+          - name: ids
+            type: intSlice
+            hidden: true
+      - name: delete
+        args: only-valid
+        valid_args: [alice, bob]
+        run: delete
+`
+
+func executeSpec(t *testing.T, cmd *cobra.Command, args ...string) (string, error) {
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs(args)
+	_, err := cmd.ExecuteC()
+	return buf.String(), err
+}
+
+func TestBuild(t *testing.T) {
+	spec, err := Parse([]byte(testSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gotArgs []string
+	var gotCmd *cobra.Command
+	handler := func(cmd *cobra.Command, args []string) error {
+		gotCmd, gotArgs = cmd, args
+		return nil
+	}
+	rootCmd, err := spec.Build(Registry{"create": handler, "delete": handler})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := executeSpec(t, rootCmd, "user", "new", "bob", "-v", "--team", "web", "--groups", "qa"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if gotCmd == nil || gotCmd.Name() != "create" || !reflect.DeepEqual(gotArgs, []string{"bob"}) {
+		t.Fatalf("Expected create to run with bob, got %v with %v", gotCmd, gotArgs)
+	}
+	if gotCmd.Use != "create <name>" || gotCmd.Short != "Create a user" || gotCmd.Long != "Create a user and add it to its team." {
+		t.Errorf("Unexpected declaration %q, %q, %q", gotCmd.Use, gotCmd.Short, gotCmd.Long)
+	}
+	if gotCmd.Parent().Annotations["group"] != "admin" {
+		t.Errorf("Expected the annotations of user, got %v", gotCmd.Parent().Annotations)
+	}
+
+	flags := gotCmd.Flags()
+	if v, _ := flags.GetBool("verbose"); !v {
+		t.Error("Expected the persistent flag verbose to be set")
+	}
+	if v, _ := flags.GetString("team"); v != "web" {
+		t.Errorf("Expected team web, got %q", v)
+	}
+	if v, _ := flags.GetInt("quota"); v != 10 {
+		t.Errorf("Expected the default quota 10, got %d", v)
+	}
+	if v, _ := flags.GetDuration("expiry"); v != 720*time.Hour {
+		t.Errorf("Expected the default expiry 720h, got %v", v)
+	}
+	if v, _ := flags.GetStringSlice("groups"); !reflect.DeepEqual(v, []string{"qa"}) {
+		t.Errorf("Expected groups [qa], got %v", v)
+	}
+	if v, _ := flags.GetFloat64("ratio"); v != 0.5 {
+		t.Errorf("Expected the default ratio 0.5, got %v", v)
+	}
+	if f := flags.Lookup("ids"); f == nil || !f.Hidden || f.Value.Type() != "intSlice" {
+		t.Errorf("Expected the hidden intSlice flag ids, got %v", f)
+	}
+
+	// The args policies and the required flags are enforced
+	if _, err := executeSpec(t, rootCmd, "user", "create", "bob", "alice", "-t", "web"); err == nil || !strings.Contains(err.Error(), "accepts 1 arg(s)") {
+		t.Errorf("Expected an error for the number of arguments, got %v", err)
+	}
+	// The flags keep their values between executions
+	rootCmd, _ = spec.Build(Registry{"create": handler, "delete": handler})
+	if _, err := executeSpec(t, rootCmd, "user", "create", "bob"); err == nil || !strings.Contains(err.Error(), `required flag(s) "team" not set`) {
+		t.Errorf("Expected an error for the required flag, got %v", err)
+	}
+	if _, err := executeSpec(t, rootCmd, "user", "delete", "carol"); err == nil || !strings.Contains(err.Error(), `invalid argument "carol"`) {
+		t.Errorf("Expected an error for the invalid argument, got %v", err)
+	}
+
+	// A command without handler shows its help
+	output, err := executeSpec(t, rootCmd, "user")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(output, "Manage the users") || !strings.Contains(output, "create      Create a user") {
+		t.Errorf("Expected the help of user, got %q", output)
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	spec, err := Parse([]byte(`
+name: app
+options:
+  - name: verbose
+    shorthand: v
+    persistent: true
+    type: bool
+commands:
+  - name: user
+    args: between:1,2
+    run: missing
+    options:
+      - name: level
+        shorthand: v
+      - name: level
+      - name: count
+        type: complex
+      - name: size
+        type: int
+        default_value: big
+  - name: group
+    usage: app team [flags]
+  - name: account
+    aliases: [user]
+  - name: list
+    args: only-valid
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = spec.Build(nil)
+	errs, ok := err.(Errors)
+	if !ok {
+		t.Fatalf("Expected Errors, got %v", err)
+	}
+	expected := []string{
+		`commands[0].args: unknown args policy "between:1,2"`,
+		`commands[0].run: unknown run handler "missing"`,
+		`commands[0].options[0].shorthand: shorthand "v" of flag "level" is already used by flag "verbose"`,
+		`commands[0].options[1].name: flag "level" is declared twice`,
+		`commands[0].options[2].type: unknown flag type "complex"`,
+		`commands[0].options[3].default_value: invalid default value "big" of int flag "size": strconv.Atoi: parsing "big": invalid syntax`,
+		`commands[1].usage: usage "app team [flags]" doesn't start with the name of the command "group"`,
+		`commands[2]: name or alias "user" of "app account" already used by commands[0]`,
+		`commands[3].args: args policy "only-valid" needs valid_args`,
+	}
+	var got []string
+	for _, e := range errs {
+		got = append(got, e.Error())
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected errors:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestBuildReservedShorthands(t *testing.T) {
+	spec, err := Parse([]byte(`
+name: app
+version: 1.0.0
+options:
+  - name: verbose
+    shorthand: v
+    persistent: true
+    type: bool
+commands:
+  - name: user
+    options:
+      - name: host
+        shorthand: h
+  - name: admin
+    options:
+      - name: help
+        persistent: true
+        type: bool
+      - name: host
+        shorthand: h
+    commands:
+      - name: reset
+        options:
+          - name: hard
+            shorthand: h
+            type: bool
+  - name: tool
+    version: 2.0.0
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = spec.Build(nil)
+	expected := strings.Join([]string{
+		`options[0].shorthand: shorthand "v" of flag "verbose" is already used by flag "version"`,
+		`commands[0].options[0].shorthand: shorthand "h" of flag "host" is already used by flag "help"`,
+		`commands[2].version: shorthand "v" of the version flag is already used by flag "verbose"`,
+	}, "\n")
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected errors:\n%s\ngot:\n%v", expected, err)
+	}
+
+	// The spec can define its own help and version flags
+	spec.Options = append(spec.Options, Option{Name: "version", Type: "bool"})
+	spec.Commands[0].Options[0].Shorthand = "H"
+	spec.Commands[2].Options = []Option{{Name: "version", Type: "bool"}}
+	rootCmd, err := spec.Build(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rootCmd.SetOut(new(bytes.Buffer))
+	rootCmd.SetArgs([]string{"admin", "reset", "-h"})
+	resetCmd, err := rootCmd.ExecuteC()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if hard, _ := resetCmd.Flags().GetBool("hard"); !hard {
+		t.Errorf("Expected -h to set the hard flag of %q", resetCmd.CommandPath())
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := Parse([]byte("name: app\ncommands:\n  - name: user\n    synopsys: Manage the users\n"))
+	if e, ok := err.(*Error); !ok || e.Path != "line 4" || !strings.HasPrefix(e.Msg, "field synopsys not found") {
+		t.Errorf("Expected an error for the unknown key at line 4, got %v", err)
+	}
+
+	_, err = Parse([]byte("name: app\ncommands:\n  - name: user\n    short: [\n"))
+	if e, ok := err.(*Error); !ok || e.Path != "line 4" {
+		t.Errorf("Expected a syntax error at line 4, got %v", err)
+	}
+
+	_, err = Parse([]byte("name: app\nsynopsys: An app\ndescripton: An app\n"))
+	if errs, ok := err.(Errors); !ok || len(errs) != 2 || errs[0].Path != "line 2" || errs[1].Path != "line 3" {
+		t.Errorf("Expected errors at lines 2 and 3, got %v", err)
+	}
+
+	_, err = Parse([]byte("{\n  \"name\": \"app\",\n  \"commands\": [{\"name\": 1}]\n}"))
+	if err == nil || !strings.HasPrefix(err.Error(), "line 3, column ") {
+		t.Errorf("Expected an error located in the JSON spec, got %v", err)
+	}
+
+	spec, err := Parse([]byte(`{"name": "app", "commands": [{"name": "user", "args": "max:2"}]}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if spec.Commands[0].Args != "max:2" {
+		t.Errorf("Expected the JSON spec to be parsed, got %+v", spec.Commands[0])
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cobra-spec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "cli.yaml")
+	if err := ioutil.WriteFile(path, []byte("name: app\nargs: exact\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = Load(path, nil)
+	expected := path + `: args: args policy "exact" takes 1 number(s) of arguments, e.g. "exact:2"`
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected error %q, got %v", expected, err)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("name: app\nsynopsys: An app\ndescripton: An app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = Load(path, nil)
+	expected = path + ": line 2: field synopsys not found in type spec.Command\n" +
+		path + ": line 3: field descripton not found in type spec.Command"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected error %q, got %v", expected, err)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("name: app\nargs: max:1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rootCmd, err := Load(path, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rootCmd.Name() != "app" || rootCmd.Args == nil {
+		t.Errorf("Expected the app command with an args policy, got %v", rootCmd)
+	}
+}
+
+func TestArgsPolicy(t *testing.T) {
+	testCases := []struct {
+		policy string
+		args   []string
+		valid  bool
+	}{
+		{"none", nil, true},
+		{"none", []string{"a"}, false},
+		{"arbitrary", []string{"a", "b"}, true},
+		{"only-valid", []string{"a"}, true},
+		{"only-valid", []string{"c"}, false},
+		{"exact:2", []string{"a", "b"}, true},
+		{"exact:2", []string{"a"}, false},
+		{"exact-valid:1", []string{"c"}, false},
+		{"min:1", nil, false},
+		{"max:1", []string{"a", "b"}, false},
+		{"range:1,3", []string{"a", "b", "a"}, true},
+		{"range:1,3", nil, false},
+	}
+	for _, tc := range testCases {
+		args, err := ArgsPolicy(tc.policy)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.policy, err)
+			continue
+		}
+		cmd := &cobra.Command{Use: "c", ValidArgs: []string{"a", "b"}}
+		if err := args(cmd, tc.args); (err == nil) != tc.valid {
+			t.Errorf("%s: expected %v to be valid: %v, got %v", tc.policy, tc.args, tc.valid, err)
+		}
+	}
+
+	for _, policy := range []string{"", "exact", "exact:x", "range:1", "range:3,1", "none:1", "any"} {
+		if _, err := ArgsPolicy(policy); err == nil {
+			t.Errorf("Expected an error for the policy %q", policy)
+		}
+	}
+}
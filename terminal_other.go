@@ -0,0 +1,10 @@
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package cobra
+
+// terminalWidth returns the number of columns of the terminal with the file
+// descriptor fd, and false if fd is not a terminal.
+// Terminals are not detected on this platform.
+func terminalWidth(fd uintptr) (int, bool) {
+	return 0, false
+}
@@ -0,0 +1,21 @@
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package cobra
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// terminalWidth returns the number of columns of the terminal with the file
+// descriptor fd, and false if fd is not a terminal.
+func terminalWidth(fd uintptr) (int, bool) {
+	var ws struct {
+		Row, Col, Xpixel, Ypixel uint16
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 {
+		return 0, false
+	}
+	return int(ws.Col), true
+}
@@ -0,0 +1,185 @@
+package cobra
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// AliasSource provides the aliases defined by the users of a program, such as
+// "co" for "checkout --track".  The aliases are given by name, with the
+// arguments they expand to.
+type AliasSource interface {
+	// Aliases returns the arguments of the aliases by name.
+	Aliases() (map[string][]string, error)
+}
+
+// MapAliasSource is an AliasSource holding the aliases in memory.
+//
+// Example:
+//   cobra.MapAliasSource{"co": {"checkout", "--track"}}
+type MapAliasSource map[string][]string
+
+// Aliases returns the aliases of the map.
+func (m MapAliasSource) Aliases() (map[string][]string, error) {
+	return m, nil
+}
+
+// FileAliasSource is an AliasSource reading the aliases from the file at the
+// given path.  Each line of the file defines an alias as "name = arguments",
+// the arguments being separated by spaces.  Empty lines and lines starting
+// with '#' are ignored.  A missing file defines no aliases.
+//
+// Example:
+//   # ~/.config/app/aliases
+//   co = checkout --track
+//   st = status --short
+type FileAliasSource string
+
+// Aliases returns the aliases defined in the file.
+func (path FileAliasSource) Aliases() (map[string][]string, error) {
+	file, err := os.Open(string(path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	aliases := make(map[string][]string)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		nameAndArgs := strings.SplitN(text, "=", 2)
+		name := strings.TrimSpace(nameAndArgs[0])
+		if len(nameAndArgs) != 2 || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("%s:%d: invalid alias %q, expected \"name = arguments\"", path, line, text)
+		}
+		aliases[name] = strings.Fields(nameAndArgs[1])
+	}
+	return aliases, scanner.Err()
+}
+
+// SetAliasSource sets the source of the aliases defined by the users.  The
+// first argument naming an alias, rather than a subcommand, is replaced by the
+// arguments of the alias before looking up the command to execute.  It must
+// be called on the root command.
+func (c *Command) SetAliasSource(source AliasSource) {
+	c.aliasSource = source
+}
+
+// UserAlias is an alias defined by the users, as listed in the help.
+type UserAlias struct {
+	// Name is the name of the alias.
+	Name string
+	// Args are the arguments the alias expands to.
+	Args []string
+
+	padding int
+}
+
+// Expansion returns the arguments of the alias joined by spaces.
+func (a UserAlias) Expansion() string {
+	return strings.Join(a.Args, " ")
+}
+
+// NamePadding returns the padding for the name of the alias.
+func (a UserAlias) NamePadding() int {
+	return a.padding
+}
+
+// UserAliases returns the active aliases defined by the users, sorted by
+// name.  The aliases shadowed by a subcommand are omitted, as are the aliases
+// of a source returning an error, which is only reported when an argument
+// which isn't a subcommand is looked up in the aliases.  Only the root
+// command has aliases.
+func (c *Command) UserAliases() []UserAlias {
+	if c.HasParent() || c.aliasSource == nil {
+		return nil
+	}
+	aliases, err := c.aliasSource.Aliases()
+	if err != nil {
+		return nil
+	}
+	padding := minNamePadding
+	userAliases := make([]UserAlias, 0, len(aliases))
+	for name, args := range aliases {
+		if c.hasSubCommand(name) {
+			continue
+		}
+		userAliases = append(userAliases, UserAlias{Name: name, Args: args})
+		if len(name) > padding {
+			padding = len(name)
+		}
+	}
+	sort.Slice(userAliases, func(i, j int) bool { return userAliases[i].Name < userAliases[j].Name })
+	for i := range userAliases {
+		userAliases[i].padding = padding
+	}
+	return userAliases
+}
+
+// HasUserAliases returns true if the command has active aliases defined by
+// the users.
+func (c *Command) HasUserAliases() bool {
+	return len(c.UserAliases()) > 0
+}
+
+// hasSubCommand returns true if name is the name or an alias of a subcommand.
+func (c *Command) hasSubCommand(name string) bool {
+	for _, cmd := range c.commands {
+		if cmd.Name() == name || cmd.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandUserAliases returns args with the first argument which is not a flag
+// replaced by the arguments of the user alias it names, repeatedly if the
+// alias expands to another alias.  Subcommands take precedence over aliases,
+// and are found without reading the aliases, so that an invalid source of
+// aliases doesn't prevent running them.  It returns an error if an alias
+// expands to itself, or if the aliases are needed but can't be read.
+func (c *Command) expandUserAliases(args []string) ([]string, error) {
+	if c.HasParent() || c.aliasSource == nil {
+		return args, nil
+	}
+	indexes := nonFlagArgIndexes(args, c)
+	if len(indexes) == 0 || c.hasSubCommand(args[indexes[0]]) {
+		return args, nil
+	}
+	i := indexes[0]
+	aliases, err := c.aliasSource.Aliases()
+	if err != nil {
+		return nil, err
+	}
+
+	var expanded []string
+	for {
+		name := args[i]
+		aliasArgs, ok := aliases[name]
+		if !ok || c.hasSubCommand(name) {
+			return args, nil
+		}
+		for _, previous := range expanded {
+			if previous == name {
+				chain := strings.Join(append(expanded, name), " -> ")
+				return nil, errors.New(c.Translate("alias %q expands to itself: %s", expanded[0], chain))
+			}
+		}
+		expanded = append(expanded, name)
+
+		args = append(append(append([]string{}, args[:i]...), aliasArgs...), args[i+1:]...)
+		if len(aliasArgs) == 0 || isFlagArg(aliasArgs[0]) {
+			return args, nil
+		}
+	}
+}
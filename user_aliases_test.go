@@ -0,0 +1,221 @@
+package cobra
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newUserAliasesTestCommand(gotArgs *[]string, gotTrack *bool) *Command {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	checkoutCmd := &Command{
+		Use:       "checkout",
+		Short:     "Check out a branch",
+		ValidArgs: []string{"feature", "main"},
+		Run: func(cmd *Command, args []string) {
+			*gotArgs = args
+			*gotTrack, _ = cmd.Flags().GetBool("track")
+		},
+	}
+	checkoutCmd.Flags().Bool("track", false, "track the remote branch")
+	statusCmd := &Command{Use: "status", Aliases: []string{"st"}, Run: emptyRun}
+	rootCmd.AddCommand(checkoutCmd, statusCmd)
+	rootCmd.SetAliasSource(MapAliasSource{
+		"co":    {"checkout", "--track"},
+		"cob":   {"co", "main"},
+		"st":    {"checkout"}, // Shadowed by the alias of status
+		"loop":  {"again"},
+		"again": {"loop"},
+	})
+	return rootCmd
+}
+
+func TestUserAliases(t *testing.T) {
+	var gotArgs []string
+	var gotTrack bool
+	rootCmd := newUserAliasesTestCommand(&gotArgs, &gotTrack)
+
+	if _, err := executeCommand(rootCmd, "co", "feature"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(gotArgs, []string{"feature"}) || !gotTrack {
+		t.Errorf("Expected checkout --track feature, got args %v and track %v", gotArgs, gotTrack)
+	}
+
+	// Aliases expanding to aliases
+	gotArgs, gotTrack = nil, false
+	if _, err := executeCommand(rootCmd, "cob", "extra"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(gotArgs, []string{"main", "extra"}) || !gotTrack {
+		t.Errorf("Expected checkout --track main extra, got args %v and track %v", gotArgs, gotTrack)
+	}
+
+	// Subcommands take precedence over aliases
+	c, _, err := rootCmd.Find([]string{"st"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if c.Name() != "status" {
+		t.Errorf("Expected the status command, got %q", c.Name())
+	}
+}
+
+func TestUserAliasesFlagValue(t *testing.T) {
+	var gotArgs []string
+	var gotTrack bool
+	rootCmd := newUserAliasesTestCommand(&gotArgs, &gotTrack)
+	rootCmd.PersistentFlags().String("name", "", "")
+
+	// The value of a flag named like an alias is not expanded
+	if _, err := executeCommand(rootCmd, "--name", "co", "co", "feature"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(gotArgs, []string{"feature"}) || !gotTrack {
+		t.Errorf("Expected checkout --track feature, got args %v and track %v", gotArgs, gotTrack)
+	}
+	c, args, err := rootCmd.Find([]string{"--name", "co", "co"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if c.Name() != "checkout" || !reflect.DeepEqual(args, []string{"--name", "co", "--track"}) {
+		t.Errorf("Expected checkout with --name co --track, got %q with %v", c.Name(), args)
+	}
+}
+
+// invalidAliasSource is an AliasSource failing to read its aliases.
+type invalidAliasSource struct{}
+
+func (invalidAliasSource) Aliases() (map[string][]string, error) {
+	return nil, errors.New("aliases:1: invalid alias")
+}
+
+func TestUserAliasesInvalidSource(t *testing.T) {
+	rootCmd := newUserAliasesTestCommand(new([]string), new(bool))
+	rootCmd.SetAliasSource(invalidAliasSource{})
+
+	// The subcommands run without the aliases
+	for _, args := range [][]string{{"status"}, {"help"}, {ShellCompNoDescRequestCmd, "st"}} {
+		if _, err := executeCommand(rootCmd, args...); err != nil {
+			t.Errorf("%v: unexpected error: %v", args, err)
+		}
+	}
+
+	_, err := executeCommand(rootCmd, "co")
+	if err == nil || err.Error() != "aliases:1: invalid alias" {
+		t.Errorf("Expected the error of the aliases, got %v", err)
+	}
+}
+
+func TestUserAliasesRecursive(t *testing.T) {
+	rootCmd := newUserAliasesTestCommand(new([]string), new(bool))
+
+	_, err := executeCommand(rootCmd, "loop")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	expected := `alias "loop" expands to itself: loop -> again -> loop`
+	if err.Error() != expected {
+		t.Errorf("Expected error %q, got %q", expected, err.Error())
+	}
+}
+
+func TestUserAliasesHelp(t *testing.T) {
+	rootCmd := newUserAliasesTestCommand(new([]string), new(bool))
+
+	output, err := executeCommand(rootCmd, "--help")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringContains(t, output, "User Aliases:\n"+
+		"  again       loop\n"+
+		"  co          checkout --track\n"+
+		"  cob         co main\n"+
+		"  loop        again\n")
+	checkStringOmits(t, output, "  st          checkout")
+
+	output, err = executeCommand(rootCmd, "checkout", "--help")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringOmits(t, output, "User Aliases:")
+}
+
+func TestUserAliasesCompletion(t *testing.T) {
+	rootCmd := newUserAliasesTestCommand(new([]string), new(bool))
+
+	output, err := executeCommand(rootCmd, ShellCompRequestCmd, "c")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := strings.Join([]string{
+		"checkout\tCheck out a branch",
+		"co\talias for \"checkout --track\"",
+		"cob\talias for \"co main\"",
+		":4",
+		"Completion ended with directive: ShellCompDirectiveNoFileComp", ""}, "\n")
+	if output != expected {
+		t.Errorf("expected: %q, got: %q", expected, output)
+	}
+
+	// The arguments of the command are completed after the expansion of the alias
+	output, err = executeCommand(rootCmd, ShellCompNoDescRequestCmd, "co", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected = strings.Join([]string{
+		"feature",
+		"main",
+		":4",
+		"Completion ended with directive: ShellCompDirectiveNoFileComp", ""}, "\n")
+	if output != expected {
+		t.Errorf("expected: %q, got: %q", expected, output)
+	}
+
+	// The flags set by the alias are not completed again
+	output, err = executeCommand(rootCmd, ShellCompNoDescRequestCmd, "co", "--tr")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkStringOmits(t, output, "--track")
+}
+
+func TestFileAliasSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cobra-aliases")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "aliases")
+	content := "# Aliases\n\nco = checkout --track\n  st=status   --short \n"
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	aliases, err := FileAliasSource(path).Aliases()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string][]string{
+		"co": {"checkout", "--track"},
+		"st": {"status", "--short"},
+	}
+	if !reflect.DeepEqual(aliases, expected) {
+		t.Errorf("Expected aliases %v, got %v", expected, aliases)
+	}
+
+	if aliases, err := FileAliasSource(filepath.Join(dir, "missing")).Aliases(); err != nil || len(aliases) != 0 {
+		t.Errorf("Expected no aliases for a missing file, got %v and error %v", aliases, err)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("co checkout\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FileAliasSource(path).Aliases(); err == nil || !strings.Contains(err.Error(), ":1: invalid alias") {
+		t.Errorf("Expected an invalid alias error, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package cobra
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// VersionInfo holds the version information of a program.  It is returned
+// by Command.VersionInfo(), and is available to the version template as
+// {{.VersionInfo}}.
+type VersionInfo struct {
+	// Name is the name of the command.
+	Name string `json:"name" yaml:"name"`
+	// Version is the Version of the command, or else the version of the
+	// main module of the program if it was built from a tagged version.
+	Version string `json:"version" yaml:"version"`
+	// Module is the path of the main module of the program.
+	Module string `json:"module,omitempty" yaml:"module,omitempty"`
+	// Revision is the revision of the version control system the program was
+	// built from.
+	Revision string `json:"revision,omitempty" yaml:"revision,omitempty"`
+	// Dirty is true if the program was built with local modifications.
+	Dirty bool `json:"dirty" yaml:"dirty"`
+	// CommitTime is the time of the Revision, in RFC3339 format.
+	CommitTime string `json:"commitTime,omitempty" yaml:"commitTime,omitempty"`
+	// BuildTime is the time the program was built, as set in BuildTime.
+	BuildTime string `json:"buildTime,omitempty" yaml:"buildTime,omitempty"`
+	// GoVersion is the version of Go the program was built with.
+	GoVersion string `json:"goVersion" yaml:"goVersion"`
+}
+
+// BuildTime is the time the program was built, in RFC3339 format, reported
+// by the version information.  The build information embedded by Go doesn't
+// include it, so it is set when building the program.
+//
+// Example:
+//   go build -ldflags "-X github.com/spf13/cobra.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var BuildTime string
+
+// readBuildInfo returns the build information of the program.
+// It can be replaced for testing.
+var readBuildInfo = debug.ReadBuildInfo
+
+// VersionInfo returns the version information of the command, completed
+// with the build information embedded in the program.  The revision, dirty
+// flag and commit time are only available for programs built with Go 1.18
+// or later from a version control checkout.
+func (c *Command) VersionInfo() *VersionInfo {
+	info := &VersionInfo{Name: c.Name(), Version: c.Version, BuildTime: BuildTime, GoVersion: runtime.Version()}
+	if bi, ok := readBuildInfo(); ok && bi != nil {
+		info.Module = bi.Main.Path
+		if info.Version == "" && bi.Main.Version != "(devel)" {
+			info.Version = bi.Main.Version
+		}
+		addBuildSettings(info, bi)
+	}
+	return info
+}
+
+// AddVersionCommand adds a "version" subcommand to c, printing the version
+// of c with the version template.  With --output, the version information
+// is printed as JSON or YAML, or the version alone is printed with "short".
+// It returns the version command so that it can be customized.
+func (c *Command) AddVersionCommand() *Command {
+	var output string
+	versionCmd := &Command{
+		Use:   "version",
+		Short: c.Translate("Print the version information"),
+		Args:  NoArgs,
+		RunE: func(cmd *Command, args []string) error {
+			return c.printVersion(cmd.OutOrStdout(), output)
+		},
+	}
+	versionCmd.Flags().VarP(NewEnumValue(&output, "", "json", "yaml", "short"), "output", "o",
+		c.Translate("output format: json, yaml or short"))
+	c.AddCommand(versionCmd)
+	return versionCmd
+}
+
+// printVersion prints the version of the command to w in the given format,
+// or with the version template if the format is empty.
+func (c *Command) printVersion(w io.Writer, format string) error {
+	switch format {
+	case "json":
+		b, err := json.MarshalIndent(c.VersionInfo(), "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", b)
+		return err
+	case "yaml":
+		_, err := io.WriteString(w, c.VersionInfo().yaml())
+		return err
+	case "short":
+		_, err := fmt.Fprintln(w, c.VersionInfo().Version)
+		return err
+	default:
+		return tmpl(w, c.VersionTemplate(), c)
+	}
+}
+
+// yaml returns the version information in the YAML format, with the keys and
+// the omitted empty values of the JSON format.  The strings are quoted as in
+// JSON, which is valid in YAML, so that no YAML library is needed.
+func (info *VersionInfo) yaml() string {
+	var b strings.Builder
+	field := func(key string, value interface{}, omitEmpty bool) {
+		if omitEmpty && value == "" {
+			return
+		}
+		encoded, _ := json.Marshal(value)
+		fmt.Fprintf(&b, "%s: %s\n", key, encoded)
+	}
+	field("name", info.Name, false)
+	field("version", info.Version, false)
+	field("module", info.Module, true)
+	field("revision", info.Revision, true)
+	field("dirty", info.Dirty, false)
+	field("commitTime", info.CommitTime, true)
+	field("buildTime", info.BuildTime, true)
+	field("goVersion", info.GoVersion, false)
+	return b.String()
+}
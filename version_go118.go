@@ -0,0 +1,25 @@
+// +build go1.18
+
+package cobra
+
+import (
+	"runtime/debug"
+)
+
+// addBuildSettings completes info with the version control settings and the
+// Go version of the build information.
+func addBuildSettings(info *VersionInfo, bi *debug.BuildInfo) {
+	if bi.GoVersion != "" {
+		info.GoVersion = bi.GoVersion
+	}
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			info.Revision = s.Value
+		case "vcs.modified":
+			info.Dirty = s.Value == "true"
+		case "vcs.time":
+			info.CommitTime = s.Value
+		}
+	}
+}
@@ -0,0 +1,41 @@
+// +build go1.18
+
+package cobra
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func TestVersionInfoBuildSettings(t *testing.T) {
+	defer func(f func() (*debug.BuildInfo, bool)) { readBuildInfo = f }(readBuildInfo)
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			GoVersion: "go1.18",
+			Main:      debug.Module{Path: "example.com/prog", Version: "(devel)"},
+			Settings: []debug.BuildSetting{
+				{Key: "vcs.revision", Value: "0123456789abcdef"},
+				{Key: "vcs.time", Value: "2021-06-01T12:00:00Z"},
+				{Key: "vcs.modified", Value: "true"},
+			},
+		}, true
+	}
+
+	defer func(buildTime string) { BuildTime = buildTime }(BuildTime)
+	BuildTime = "2021-06-02T08:30:00Z"
+
+	rootCmd := &Command{Use: "prog", Version: "1.0.0", Run: emptyRun}
+	expected := VersionInfo{
+		Name:       "prog",
+		Version:    "1.0.0",
+		Module:     "example.com/prog",
+		Revision:   "0123456789abcdef",
+		Dirty:      true,
+		CommitTime: "2021-06-01T12:00:00Z",
+		BuildTime:  "2021-06-02T08:30:00Z",
+		GoVersion:  "go1.18",
+	}
+	if info := rootCmd.VersionInfo(); *info != expected {
+		t.Errorf("Expected: %#v\nGot: %#v", expected, info)
+	}
+}
@@ -0,0 +1,11 @@
+// +build !go1.18
+
+package cobra
+
+import (
+	"runtime/debug"
+)
+
+// addBuildSettings does nothing, the build information of Go versions before
+// 1.18 has no version control settings.
+func addBuildSettings(info *VersionInfo, bi *debug.BuildInfo) {}
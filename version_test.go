@@ -0,0 +1,93 @@
+package cobra
+
+import (
+	"encoding/json"
+	"runtime"
+	"runtime/debug"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func setTestBuildInfo(version string) func() {
+	saved := readBuildInfo
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{Path: "example.com/prog", Main: debug.Module{Path: "example.com/prog", Version: version}}, true
+	}
+	return func() { readBuildInfo = saved }
+}
+
+func TestVersionInfo(t *testing.T) {
+	defer setTestBuildInfo("v1.2.3")()
+
+	rootCmd := &Command{Use: "prog", Version: "2.0.0", Run: emptyRun}
+	info := rootCmd.VersionInfo()
+	if info.Name != "prog" || info.Version != "2.0.0" || info.Module != "example.com/prog" || info.GoVersion == "" {
+		t.Errorf("Unexpected version info: %#v", info)
+	}
+
+	// The version of the main module is used without Version
+	rootCmd.Version = ""
+	if v := rootCmd.VersionInfo().Version; v != "v1.2.3" {
+		t.Errorf("Expected the version of the main module, got %q", v)
+	}
+
+	defer setTestBuildInfo("(devel)")()
+	if v := rootCmd.VersionInfo().Version; v != "" {
+		t.Errorf("Expected no version for a development build, got %q", v)
+	}
+}
+
+func TestVersionCommand(t *testing.T) {
+	defer setTestBuildInfo("(devel)")()
+
+	rootCmd := &Command{Use: "prog", Version: "1.0.0", Run: emptyRun}
+	rootCmd.AddVersionCommand()
+
+	output, err := executeCommand(rootCmd, "version")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if output != "prog version 1.0.0\n" {
+		t.Errorf("Unexpected output: %q", output)
+	}
+
+	output, err = executeCommand(rootCmd, "version", "--output", "short")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if output != "1.0.0\n" {
+		t.Errorf("Unexpected output: %q", output)
+	}
+
+	expected := VersionInfo{Name: "prog", Version: "1.0.0", Module: "example.com/prog", GoVersion: runtime.Version()}
+
+	output, err = executeCommand(rootCmd, "version", "-o", "json")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	var info VersionInfo
+	if err := json.Unmarshal([]byte(output), &info); err != nil {
+		t.Fatalf("Invalid JSON output %q: %v", output, err)
+	}
+	if info != expected {
+		t.Errorf("Expected: %#v\nGot: %#v", expected, info)
+	}
+	checkStringContains(t, output, `"goVersion": `)
+
+	output, err = executeCommand(rootCmd, "version", "-o", "yaml")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	info = VersionInfo{}
+	if err := yaml.Unmarshal([]byte(output), &info); err != nil {
+		t.Fatalf("Invalid YAML output %q: %v", output, err)
+	}
+	if info != expected {
+		t.Errorf("Expected: %#v\nGot: %#v", expected, info)
+	}
+
+	if _, err := executeCommand(rootCmd, "version", "-o", "xml"); err == nil {
+		t.Error("Expected an error for an invalid output format")
+	}
+}
@@ -96,7 +96,7 @@ _%[1]s()
     local shellCompDirectiveFilterDirs=%[7]d
 
     local lastParam lastChar flagPrefix requestComp out directive compCount comp lastComp
-    local -a completions
+    local -a completions afterWords
 
     __%[1]s_debug "\n========= starting completion logic =========="
     __%[1]s_debug "CURRENT: ${CURRENT}, words[*]: ${words[*]}"
@@ -104,9 +104,12 @@ _%[1]s()
     # The user could have moved the cursor backwards on the command-line.
     # We need to trigger completion from the $CURRENT location, so we need
     # to truncate the command-line ($words) up to the $CURRENT location.
+    # The words following it are sent separately so the program can
+    # consider them too.
     # (We cannot use $CURSOR as its value does not work when a command is an alias.)
+    afterWords=(${words[CURRENT+1,-1]})
     words=("${=words[1,CURRENT]}")
-    __%[1]s_debug "Truncated words[*]: ${words[*]},"
+    __%[1]s_debug "Truncated words[*]: ${words[*]}, afterWords[*]: ${afterWords[*]}"
 
     lastParam=${words[-1]}
     lastChar=${lastParam[-1]}
@@ -120,14 +123,16 @@ _%[1]s()
         flagPrefix="-P ${BASH_REMATCH}"
     fi
 
-    # Prepare the command to obtain completions
-    requestComp="${words[1]} %[2]s ${words[2,-1]}"
+    # Prepare the command to obtain completions, giving the index of the
+    # parameter to complete counted from the end
+    requestComp="${words[1]} %[2]s %[9]s=-$((${#afterWords}+1)) ${words[2,-1]}"
     if [ "${lastChar}" = "" ]; then
         # If the last parameter is complete (there is a space following it)
         # We add an extra empty parameter so we can indicate this to the go completion code.
         __%[1]s_debug "Adding extra empty parameter"
         requestComp="${requestComp} \"\""
     fi
+    requestComp="${requestComp} ${afterWords[*]}"
 
     __%[1]s_debug "About to call: eval ${requestComp}"
 
@@ -163,8 +168,20 @@ _%[1]s()
         return
     fi
 
+    local activeHelpMarker="%[8]s"
+    local endIndex=${#activeHelpMarker}
+    local startIndex=$((${#activeHelpMarker}+1))
     compCount=0
     while IFS='\n' read -r comp; do
+        # Active help hints are displayed as explanations, not as completions
+        if [ "${comp[1,$endIndex]}" = "$activeHelpMarker" ]; then
+            comp="${comp[$startIndex,-1]}"
+            __%[1]s_debug "Adding active help: ${comp}"
+            if [ -n "$comp" ]; then
+                compadd -x "${comp}"
+            fi
+            continue
+        fi
         if [ -n "$comp" ]; then
             # If requested, completions are returned with a description.
             # The description is preceded by a TAB character.
@@ -231,5 +248,5 @@ _%[1]s()
 }
 `, name, compCmd,
 		ShellCompDirectiveError, ShellCompDirectiveNoSpace, ShellCompDirectiveNoFileComp,
-		ShellCompDirectiveFilterFileExt, ShellCompDirectiveFilterDirs))
+		ShellCompDirectiveFilterFileExt, ShellCompDirectiveFilterDirs, activeHelpMarker, ShellCompCursorArg))
 }